@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxAltTextLength      = 1000
+	missingAltTextWarning = "no alt text provided; screen readers will get a description generated from the title"
+)
+
+var (
+	errAltTextRequired = errors.New("altText is required")
+	errAltTextTooLong  = errors.New("altText is too long")
+)
+
+// altTextRequired reports whether uploads without alt text are rejected
+// rather than warned about. Set REQUIRE_ALT_TEXT=true to enforce it.
+func altTextRequired() bool {
+	return os.Getenv("REQUIRE_ALT_TEXT") == "true"
+}
+
+// checkAltText validates alt text for a new artwork. A missing value is an
+// error when alt text is required and a warning otherwise.
+func checkAltText(altText string) (string, error) {
+	if utf8.RuneCountInString(altText) > maxAltTextLength {
+		return "", errAltTextTooLong
+	}
+	if altText != "" {
+		return "", nil
+	}
+	if altTextRequired() {
+		return "", errAltTextRequired
+	}
+	return missingAltTextWarning, nil
+}
+
+// fallbackAltText describes an artwork from its metadata for artists who
+// haven't written alt text yet.
+func fallbackAltText(artwork *models.Artwork) string {
+	kind := "Artwork"
+	if label, ok := models.Mediums[artwork.Medium]; ok && artwork.Medium != "other" {
+		kind = label
+	}
+	if label, ok := models.Categories[artwork.Category]; ok && artwork.Category != "other" {
+		kind += " (" + strings.ToLower(label) + ")"
+	}
+	return kind + " titled \"" + artwork.Title + "\""
+}
+
+// fillAltText gives every artwork without alt text a generated fallback so
+// public responses always carry one.
+func fillAltText(artworks []models.Artwork) {
+	for i := range artworks {
+		if artworks[i].AltText == "" {
+			artworks[i].AltText = fallbackAltText(&artworks[i])
+			artworks[i].AltTextGenerated = true
+		}
+	}
+}
+
+// GetMissingAltText lists the authenticated user's artworks that have no alt
+// text, so they can be fixed in one pass.
+func GetMissingAltText(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := activeArtworkFilter(bson.M{
+		"userId": userID,
+		"$or": bson.A{
+			bson.M{"altText": bson.M{"$exists": false}},
+			bson.M{"altText": ""},
+		},
+	})
+
+	total, err := database.Collection("artworks").CountDocuments(ctx, activeArtworkFilter(bson.M{"userId": userID}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+
+	cursor, err := database.Collection("artworks").Find(
+		ctx,
+		filter,
+		options.Find().
+			SetSort(bson.M{"createdAt": -1}).
+			SetProjection(bson.M{"_id": 1, "title": 1, "url": 1, "visibility": 1, "createdAt": 1}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	type missing struct {
+		ID         primitive.ObjectID `bson:"_id" json:"id"`
+		Title      string             `bson:"title" json:"title"`
+		URL        string             `bson:"url" json:"url"`
+		Visibility string             `bson:"visibility" json:"visibility"`
+		CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	}
+	artworks := []missing{}
+	if err := cursor.All(ctx, &artworks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse artworks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":    total,
+		"missing":  len(artworks),
+		"artworks": artworks,
+	})
+}
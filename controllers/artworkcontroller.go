@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/search"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// validateDescription rejects descriptions over the length limit before any
+// upload work starts.
+func validateDescription(description string) error {
+	if utf8.RuneCountInString(description) > utils.MaxDescriptionLength {
+		return utils.ErrDescriptionTooLong
+	}
+	return nil
+}
+
+// portfolioOrder sorts an artist's artworks by their manual position.
+// Artworks that were never arranged have no position and come first, newest
+// first, so fresh uploads are visible until the artist places them.
+var portfolioOrder = bson.D{{Key: "position", Value: 1}, {Key: "createdAt", Value: -1}}
+
+// pinFirst moves the pinned artworks to the front in pin order and returns
+// the ids of those that were actually present.
+func pinFirst(artworks []models.Artwork, pinnedIDs []primitive.ObjectID) ([]models.Artwork, []primitive.ObjectID) {
+	pinned := []primitive.ObjectID{}
+	if len(pinnedIDs) == 0 {
+		return artworks, pinned
+	}
+
+	byID := make(map[primitive.ObjectID]int, len(artworks))
+	for i, a := range artworks {
+		byID[a.ID] = i
+	}
+
+	ordered := make([]models.Artwork, 0, len(artworks))
+	used := map[int]bool{}
+	for _, id := range pinnedIDs {
+		if i, ok := byID[id]; ok && !used[i] {
+			used[i] = true
+			ordered = append(ordered, artworks[i])
+			pinned = append(pinned, id)
+		}
+	}
+	for i, a := range artworks {
+		if !used[i] {
+			ordered = append(ordered, a)
+		}
+	}
+	return ordered, pinned
+}
+
+// UpdateArtwork edits an artwork's details. Only the fields present in the
+// body are changed.
+func UpdateArtwork(c *gin.Context) {
+	var input struct {
+		Title       *string         `json:"title"`
+		Description *string         `json:"description"`
+		AltText     *string         `json:"altText"`
+		License     *models.License `json:"license"`
+
+		ContentRating   *string   `json:"contentRating"`
+		ContentWarnings *[]string `json:"contentWarnings"`
+
+		Tags       *[]string `json:"tags"`
+		Medium     *string   `json:"medium"`
+		Category   *string   `json:"category"`
+		Visibility *string   `json:"visibility"`
+		PublishAt  *string   `json:"publishAt"`
+
+		CommentsDisabled *bool `json:"commentsDisabled"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	artwork, ok := findOwnedArtwork(c, ctx)
+	if !ok {
+		return
+	}
+
+	set := bson.M{}
+	unset := bson.M{}
+
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "title cannot be empty"})
+			return
+		}
+		set["title"] = title
+		set["slug"] = title
+		set["searchWords"] = search.Words(title)
+	}
+
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		if description == "" {
+			unset["description"] = ""
+			unset["descriptionHtml"] = ""
+		} else {
+			rendered, err := utils.RenderMarkdown(description)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			set["description"] = description
+			set["descriptionHtml"] = rendered
+		}
+	}
+
+	if input.AltText != nil {
+		altText := strings.TrimSpace(*input.AltText)
+		if _, err := checkAltText(altText); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if altText == "" {
+			unset["altText"] = ""
+		} else {
+			set["altText"] = altText
+		}
+	}
+
+	if input.License != nil {
+		if err := normalizeLicense(input.License); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.License.CopyrightHolder == "" && artwork.License != nil {
+			input.License.CopyrightHolder = artwork.License.CopyrightHolder
+		}
+		if input.License.CopyrightYear == 0 && artwork.License != nil {
+			input.License.CopyrightYear = artwork.License.CopyrightYear
+		}
+		set["license"] = input.License
+	}
+
+	if input.ContentRating != nil {
+		if err := validateContentRating(*input.ContentRating); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if *input.ContentRating == "" {
+			unset["contentRating"] = ""
+		} else {
+			set["contentRating"] = *input.ContentRating
+		}
+	}
+
+	if input.ContentWarnings != nil {
+		warnings, err := normalizeContentWarnings(*input.ContentWarnings)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if len(warnings) > 0 {
+			set["contentWarnings"] = warnings
+		} else {
+			unset["contentWarnings"] = ""
+		}
+	}
+
+	if input.Tags != nil {
+		if tags := utils.NormalizeTags(*input.Tags); len(tags) > 0 {
+			set["tags"] = tags
+		} else {
+			unset["tags"] = ""
+		}
+	}
+
+	medium, category := "", ""
+	if input.Medium != nil {
+		medium = *input.Medium
+	}
+	if input.Category != nil {
+		category = *input.Category
+	}
+	if err := validateTaxonomy(medium, category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for field, value := range map[string]*string{"medium": input.Medium, "category": input.Category} {
+		if value == nil {
+			continue
+		}
+		if *value == "" {
+			unset[field] = ""
+		} else {
+			set[field] = *value
+		}
+	}
+
+	if input.CommentsDisabled != nil {
+		if *input.CommentsDisabled {
+			set["commentsDisabled"] = true
+		} else {
+			unset["commentsDisabled"] = ""
+		}
+	}
+
+	if input.Visibility != nil || input.PublishAt != nil {
+		visibility := artwork.Visibility
+		if input.Visibility != nil {
+			visibility = *input.Visibility
+		}
+		publishAt := artwork.PublishAt
+		if input.PublishAt != nil {
+			var err error
+			if publishAt, err = parsePublishAt(*input.PublishAt); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		vset, vunset, err := visibilityUpdate(artwork, visibility, publishAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for k, v := range vset {
+			set[k] = v
+		}
+		for k, v := range vunset {
+			unset[k] = v
+		}
+	}
+
+	if len(set) == 0 && len(unset) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nothing to update"})
+		return
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	var updated models.Artwork
+	err := database.Collection("artworks").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": artwork.ID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update artwork"})
+		return
+	}
+	// An artwork newly rated mature needs blurred previews to be gated.
+	if input.ContentRating != nil {
+		if err := ensureBlurredPreviews(ctx, updated); err != nil {
+			fmt.Println("Blurred preview error:", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// ReorderArtworks sets the portfolio order. artworkIds lists artworks in
+// their new order; any of the artist's other artworks keep their current
+// relative order after them, so a client may send just the part it moved.
+func ReorderArtworks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		ArtworkIDs []string `json:"artworkIds" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	collection := database.Collection("artworks")
+	cursor, err := collection.Find(
+		ctx,
+		activeArtworkFilter(bson.M{"userId": userID}),
+		options.Find().SetSort(portfolioOrder).SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	var current []models.Artwork
+	if err := cursor.All(ctx, &current); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse artworks"})
+		return
+	}
+
+	owned := make(map[primitive.ObjectID]bool, len(current))
+	for _, a := range current {
+		owned[a.ID] = true
+	}
+
+	order := make([]primitive.ObjectID, 0, len(current))
+	listed := map[primitive.ObjectID]bool{}
+	for _, hex := range input.ArtworkIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil || !owned[id] || listed[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "artworkIds must be your own artworks, each listed once"})
+			return
+		}
+		listed[id] = true
+		order = append(order, id)
+	}
+	for _, a := range current {
+		if !listed[a.ID] {
+			order = append(order, a.ID)
+		}
+	}
+
+	writes := make([]mongo.WriteModel, len(order))
+	for i, id := range order {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "userId": userID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i}})
+	}
+	if len(writes) > 0 {
+		if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reorder artworks"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "artworks reordered",
+		"artworkIds": order,
+	})
+}
+
+// SetPinnedArtworks replaces the artworks pinned to the top of the
+// portfolio, in the given order. Send an empty list to unpin everything.
+func SetPinnedArtworks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		ArtworkIDs []string `json:"artworkIds" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if len(input.ArtworkIDs) > models.MaxPinnedArtworks {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d artworks can be pinned", models.MaxPinnedArtworks)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ids := make([]primitive.ObjectID, 0, len(input.ArtworkIDs))
+	seen := map[primitive.ObjectID]bool{}
+	for _, hex := range input.ArtworkIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil || seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "artworkIds must be your own artworks, each listed once"})
+			return
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) > 0 {
+		n, err := database.Collection("artworks").CountDocuments(
+			ctx,
+			activeArtworkFilter(bson.M{"_id": bson.M{"$in": ids}, "userId": userID}),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to pin artworks"})
+			return
+		}
+		if int(n) != len(ids) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "artworkIds must be your own artworks, each listed once"})
+			return
+		}
+	}
+
+	_, err := database.Collection("users").UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"pinned_artworks": ids, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to pin artworks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "pinned artworks updated",
+		"pinnedArtworkIds": ids,
+	})
+}
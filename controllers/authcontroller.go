@@ -12,6 +12,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/nerokome/artfolio-backend/database"
 	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/search"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -63,13 +64,14 @@ func Signup(c *gin.Context) {
 	}
 
 	user := models.User{
-		ID:        primitive.NewObjectID(),
-		FullName:  cleanName,
-		Email:     cleanEmail,
-		Password:  string(hashedPassword),
-		Role:      "user",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:          primitive.NewObjectID(),
+		FullName:    cleanName,
+		SearchWords: search.Words(cleanName),
+		Email:       cleanEmail,
+		Password:    string(hashedPassword),
+		Role:        "user",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 
 	_, err = userCollection.InsertOne(ctx, user)
@@ -0,0 +1,543 @@
+package controllers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/middleware"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const bulkMaxEntries = 500
+
+// bulkJobQueue wakes the worker for newly created jobs. Jobs that don't fit
+// are still picked up by the worker's periodic sweep.
+var bulkJobQueue = make(chan primitive.ObjectID, 100)
+
+// manifestEntry is one row of an optional manifest.csv / manifest.json at
+// the root of the archive.
+type manifestEntry struct {
+	Filename    string   `json:"filename"`
+	Title       string   `json:"title"`
+	Tags        []string `json:"tags"`
+	Visibility  string   `json:"visibility"`
+	PublishAt   string   `json:"publishAt"`
+	Medium      string   `json:"medium"`
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	AltText     string   `json:"altText"`
+	License     string   `json:"license"`
+	CustomText  string   `json:"customText"`
+	Attribution string   `json:"attribution"`
+
+	ContentRating   string   `json:"contentRating"`
+	ContentWarnings []string `json:"contentWarnings"`
+}
+
+func bulkArchivePath(id primitive.ObjectID) string {
+	return filepath.Join(uploadStagingDir(), id.Hex()+".zip")
+}
+
+func isManifest(name string) bool {
+	return name == "manifest.csv" || name == "manifest.json"
+}
+
+// skipArchiveEntry ignores folders and OS clutter such as __MACOSX/ and
+// dotfiles.
+func skipArchiveEntry(f *zip.File) bool {
+	if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+		return true
+	}
+	return strings.HasPrefix(path.Base(f.Name), ".")
+}
+
+func parseManifest(f *zip.File) (map[string]manifestEntry, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var entries []manifestEntry
+	if strings.HasSuffix(f.Name, ".json") {
+		if err := json.NewDecoder(io.LimitReader(rc, 5*1024*1024)).Decode(&entries); err != nil {
+			return nil, err
+		}
+	} else {
+		rows, err := csv.NewReader(io.LimitReader(rc, 5*1024*1024)).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return map[string]manifestEntry{}, nil
+		}
+
+		cols := map[string]int{}
+		for i, h := range rows[0] {
+			cols[strings.ToLower(strings.TrimSpace(h))] = i
+		}
+		get := func(row []string, col string) string {
+			if i, ok := cols[col]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		for _, row := range rows[1:] {
+			entries = append(entries, manifestEntry{
+				Filename:    get(row, "filename"),
+				Title:       get(row, "title"),
+				Tags:        strings.FieldsFunc(get(row, "tags"), func(r rune) bool { return r == ';' || r == '|' }),
+				Visibility:  get(row, "visibility"),
+				PublishAt:   get(row, "publishat"),
+				Medium:      get(row, "medium"),
+				Category:    get(row, "category"),
+				Description: get(row, "description"),
+				AltText:     get(row, "alttext"),
+				License:     get(row, "license"),
+				CustomText:  get(row, "customtext"),
+				Attribution: get(row, "attribution"),
+
+				ContentRating:   get(row, "contentrating"),
+				ContentWarnings: strings.FieldsFunc(get(row, "contentwarnings"), func(r rune) bool { return r == ';' || r == '|' }),
+			})
+		}
+	}
+
+	byName := make(map[string]manifestEntry, len(entries))
+	for _, e := range entries {
+		byName[strings.TrimPrefix(e.Filename, "./")] = e
+	}
+	return byName, nil
+}
+
+// buildJobItems lists the images in an archive, applying manifest metadata
+// where present and falling back to the file name as the title.
+func buildJobItems(archive *zip.Reader) ([]models.UploadJobItem, error) {
+	manifest := map[string]manifestEntry{}
+	for _, f := range archive.File {
+		if isManifest(f.Name) {
+			m, err := parseManifest(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", f.Name, err)
+			}
+			manifest = m
+			break
+		}
+	}
+
+	items := []models.UploadJobItem{}
+	for _, f := range archive.File {
+		if skipArchiveEntry(f) || isManifest(f.Name) {
+			continue
+		}
+
+		base := path.Base(f.Name)
+		item := models.UploadJobItem{
+			Filename:   f.Name,
+			Title:      strings.TrimSuffix(base, path.Ext(base)),
+			Visibility: models.VisibilityPublic,
+			Status:     models.JobItemPending,
+		}
+
+		entry, ok := manifest[f.Name]
+		if !ok {
+			entry, ok = manifest[base]
+		}
+		if ok {
+			if t := strings.TrimSpace(entry.Title); t != "" {
+				item.Title = t
+			}
+			item.Tags = utils.NormalizeTags(entry.Tags)
+			if v := strings.ToLower(strings.TrimSpace(entry.Visibility)); v != "" {
+				item.Visibility = v
+			}
+			publishAt, err := parsePublishAt(strings.TrimSpace(entry.PublishAt))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			item.PublishAt = publishAt
+			item.Medium = strings.ToLower(strings.TrimSpace(entry.Medium))
+			item.Category = strings.ToLower(strings.TrimSpace(entry.Category))
+			if err := validateTaxonomy(item.Medium, item.Category); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			item.Description = strings.TrimSpace(entry.Description)
+			if err := validateDescription(item.Description); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			item.AltText = strings.TrimSpace(entry.AltText)
+			item.ContentRating = strings.ToLower(strings.TrimSpace(entry.ContentRating))
+			if err := validateContentRating(item.ContentRating); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			if item.ContentWarnings, err = normalizeContentWarnings(entry.ContentWarnings); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			if strings.TrimSpace(entry.License) != "" {
+				item.License = &models.License{Type: entry.License, CustomText: entry.CustomText, Attribution: entry.Attribution}
+				if err := normalizeLicense(item.License); err != nil {
+					return nil, fmt.Errorf("%s: %w", f.Name, err)
+				}
+			}
+			if err := validateVisibility(item.Visibility, item.PublishAt); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+		if _, err := checkAltText(item.AltText); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// UploadBulkArtworks accepts a ZIP of images and queues it for background
+// processing. Progress is reported through GetUploadJob.
+func UploadBulkArtworks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	if err := os.MkdirAll(uploadStagingDir(), 0o700); err != nil {
+		fmt.Println("Upload staging dir error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store archive"})
+		return
+	}
+
+	jobID := primitive.NewObjectID()
+	archivePath := bulkArchivePath(jobID)
+	if err := c.SaveUploadedFile(file, archivePath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store archive"})
+		return
+	}
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		os.Remove(archivePath)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is not a valid zip archive"})
+		return
+	}
+	items, err := buildJobItems(&archive.Reader)
+	archive.Close()
+	if err != nil {
+		os.Remove(archivePath)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(items) == 0 {
+		os.Remove(archivePath)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "archive contains no files"})
+		return
+	}
+	if len(items) > bulkMaxEntries {
+		os.Remove(archivePath)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("archive contains more than %d files", bulkMaxEntries)})
+		return
+	}
+
+	now := time.Now()
+	job := models.UploadJob{
+		ID:        jobID,
+		UserID:    userID,
+		Filename:  file.Filename,
+		Status:    models.JobQueued,
+		Total:     len(items),
+		Items:     items,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := database.Collection("upload_jobs").InsertOne(ctx, job); err != nil {
+		os.Remove(archivePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload job"})
+		return
+	}
+
+	select {
+	case bulkJobQueue <- job.ID:
+	default:
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "bulk upload queued",
+		"job":     job,
+	})
+}
+
+func GetUploadJob(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	jobID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var job models.UploadJob
+	err = database.Collection("upload_jobs").FindOne(ctx, bson.M{"_id": jobID, "userId": userID}).Decode(&job)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func GetMyUploadJobs(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.M{"createdAt": -1}).
+		SetLimit(20).
+		SetProjection(bson.M{"items": 0})
+
+	cursor, err := database.Collection("upload_jobs").Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch jobs"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []models.UploadJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count": len(jobs),
+		"jobs":  jobs,
+	})
+}
+
+// ProcessUploadJobs is the bulk upload worker. It picks up new jobs as soon
+// as they are queued and sweeps for stragglers once a minute. Jobs
+// interrupted by a restart are resumed, skipping items that already
+// finished; items cut off mid-way keep the artwork id recorded for them, so
+// they are only created again if that artwork was never saved.
+func ProcessUploadJobs() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	database.Collection("upload_jobs").UpdateMany(
+		ctx,
+		bson.M{"status": models.JobProcessing},
+		bson.M{"$set": bson.M{"status": models.JobQueued}},
+	)
+	cancel()
+
+	for {
+		select {
+		case id := <-bulkJobQueue:
+			processUploadJob(bson.M{"_id": id, "status": models.JobQueued})
+		case <-time.After(time.Minute):
+			for processUploadJob(bson.M{"status": models.JobQueued}) {
+			}
+		}
+	}
+}
+
+// processUploadJob claims one queued job matching filter and runs it. It
+// reports whether a job was found.
+func processUploadJob(filter bson.M) bool {
+	jobs := database.Collection("upload_jobs")
+
+	var job models.UploadJob
+	err := jobs.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		bson.M{"$set": bson.M{"status": models.JobProcessing, "updatedAt": time.Now()}},
+		options.FindOneAndUpdate().SetSort(bson.M{"createdAt": 1}).SetReturnDocument(options.After),
+	).Decode(&job)
+	if err != nil {
+		return false
+	}
+
+	finish := func(status, errMsg string) {
+		now := time.Now()
+		set := bson.M{"status": status, "updatedAt": now, "finishedAt": now}
+		if errMsg != "" {
+			set["error"] = errMsg
+		}
+		jobs.UpdateOne(context.Background(), bson.M{"_id": job.ID}, bson.M{"$set": set})
+		os.Remove(bulkArchivePath(job.ID))
+	}
+
+	archive, err := zip.OpenReader(bulkArchivePath(job.ID))
+	if err != nil {
+		finish(models.JobFailed, "archive is no longer available")
+		return true
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	for i, item := range job.Items {
+		prefix := fmt.Sprintf("items.%d.", i)
+
+		switch item.Status {
+		case models.JobItemPending:
+			// Record the artwork id before creating the artwork, so a
+			// restart in between can tell whether it was created.
+			id := primitive.NewObjectID()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			_, err := jobs.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": bson.M{
+				prefix + "status":    models.JobItemProcessing,
+				prefix + "artworkId": id,
+				"updatedAt":          time.Now(),
+			}})
+			cancel()
+			if err != nil {
+				fmt.Println("Failed to record bulk upload progress:", err)
+				// Hand the job back and stop draining until the next tick.
+				jobs.UpdateOne(context.Background(), bson.M{"_id": job.ID}, bson.M{"$set": bson.M{"status": models.JobQueued}})
+				return false
+			}
+			item.ArtworkID = &id
+		case models.JobItemProcessing:
+			if item.ArtworkID == nil {
+				id := primitive.NewObjectID()
+				item.ArtworkID = &id
+			}
+		default:
+			continue
+		}
+
+		var artworkID primitive.ObjectID
+		var err error
+		if item.Status == models.JobItemProcessing && artworkExists(*item.ArtworkID) {
+			// Interrupted after the artwork was saved; only the result is
+			// missing.
+			artworkID = *item.ArtworkID
+		} else {
+			artworkID, err = processJobItem(job.UserID, item, files[item.Filename])
+		}
+
+		set := bson.M{"updatedAt": time.Now()}
+		inc := bson.M{"processed": 1}
+		if err != nil {
+			set[prefix+"status"] = models.JobItemFailed
+			set[prefix+"error"] = err.Error()
+			inc["failed"] = 1
+		} else {
+			set[prefix+"status"] = models.JobItemSucceeded
+			set[prefix+"artworkId"] = artworkID
+			inc["succeeded"] = 1
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if _, err := jobs.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": set, "$inc": inc}); err != nil {
+			fmt.Println("Failed to record bulk upload progress:", err)
+		}
+		cancel()
+	}
+
+	finish(models.JobCompleted, "")
+	return true
+}
+
+// artworkExists reports whether an artwork with the given id was saved,
+// trashed or not.
+func artworkExists(id primitive.ObjectID) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	n, _ := database.Collection("artworks").CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	return n > 0
+}
+
+// processJobItem validates one archive entry with the same rules as
+// UploadMiddleware and creates its artwork.
+func processJobItem(userID primitive.ObjectID, item models.UploadJobItem, f *zip.File) (primitive.ObjectID, error) {
+	if f == nil {
+		return primitive.NilObjectID, errors.New("file missing from archive")
+	}
+
+	maxBytes := int64(middleware.ArtworkMaxUploadMB * 1024 * 1024)
+	if f.UncompressedSize64 > uint64(maxBytes) {
+		return primitive.NilObjectID, middleware.ErrFileTooLarge
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return primitive.NilObjectID, errors.New("failed to read file")
+	}
+	// The header size can lie; never read more than the limit allows.
+	data, err := io.ReadAll(io.LimitReader(rc, maxBytes+1))
+	rc.Close()
+	if err != nil {
+		return primitive.NilObjectID, errors.New("failed to read file")
+	}
+
+	contentType := http.DetectContentType(data)
+	if err := middleware.ValidateFile(int64(len(data)), contentType, middleware.ArtworkMaxUploadMB, middleware.ArtworkAllowedTypes); err != nil {
+		if err == middleware.ErrInvalidFileType {
+			return primitive.NilObjectID, fmt.Errorf("invalid file type: %s", contentType)
+		}
+		return primitive.NilObjectID, err
+	}
+
+	details := newArtworkDetails(item.Title)
+	details.ID = *item.ArtworkID
+	details.Tags = item.Tags
+	details.Visibility = item.Visibility
+	details.PublishAt = item.PublishAt
+	details.Medium = item.Medium
+	details.Category = item.Category
+	details.Description = item.Description
+	details.AltText = item.AltText
+	details.License = item.License
+	details.ContentRating = item.ContentRating
+	details.ContentWarnings = item.ContentWarnings
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	artwork, _, err := createArtwork(ctx, userID, details, data)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return artwork.ID, nil
+}
@@ -0,0 +1,669 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxCollectionDescription = 2000
+
+var errInvalidCollectionArtworks = errors.New("artworkIds must be your own artworks, each listed once")
+
+// collectionView is a collection with its artworks resolved in order.
+type collectionView struct {
+	models.Collection
+	CoverURL     string           `json:"coverUrl,omitempty"`
+	ArtworkCount int              `json:"artworkCount"`
+	Artworks     []models.Artwork `json:"artworks,omitempty"`
+}
+
+// --- Helper: load a collection owned by the authenticated user ---
+func findOwnedCollection(c *gin.Context, ctx context.Context) (*models.Collection, bool) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	collectionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection ID"})
+		return nil, false
+	}
+
+	var collection models.Collection
+	err = database.Collection("collections").FindOne(ctx, bson.M{"_id": collectionID, "userId": userID}).Decode(&collection)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "collection not found or you do not own it"})
+		return nil, false
+	}
+	return &collection, true
+}
+
+// uniqueCollectionSlug derives a slug from title that no other collection of
+// the same artist uses, adding a numeric suffix when needed.
+func uniqueCollectionSlug(ctx context.Context, userID primitive.ObjectID, title string, exclude primitive.ObjectID) (string, error) {
+	base := strings.Trim(utils.Slugify(title), "-")
+	if base == "" {
+		base = "collection"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		n, err := database.Collection("collections").CountDocuments(ctx, bson.M{
+			"userId": userID,
+			"slug":   slug,
+			"_id":    bson.M{"$ne": exclude},
+		})
+		if err != nil {
+			return "", err
+		}
+		if n == 0 {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// parseCollectionArtworks checks that every id is a distinct, non-deleted
+// artwork owned by userID.
+func parseCollectionArtworks(ctx context.Context, userID primitive.ObjectID, hexIDs []string) ([]primitive.ObjectID, error) {
+	if len(hexIDs) > models.MaxArtworksPerCollection {
+		return nil, fmt.Errorf("a collection can hold at most %d artworks", models.MaxArtworksPerCollection)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(hexIDs))
+	seen := map[primitive.ObjectID]bool{}
+	for _, hex := range hexIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil || seen[id] {
+			return nil, errInvalidCollectionArtworks
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	n, err := database.Collection("artworks").CountDocuments(
+		ctx,
+		activeArtworkFilter(bson.M{"_id": bson.M{"$in": ids}, "userId": userID}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if int(n) != len(ids) {
+		return nil, errInvalidCollectionArtworks
+	}
+	return ids, nil
+}
+
+// resolveCollection loads the artworks of a collection that match filter,
+// keeping the collection's order. ArtworkIDs is narrowed to what the filter
+// lets through, and the cover falls back to the first visible artwork when
+// the chosen one is hidden.
+func resolveCollection(ctx context.Context, collection models.Collection, filter bson.M) (collectionView, error) {
+	view := collectionView{Collection: collection, Artworks: []models.Artwork{}}
+	if len(collection.ArtworkIDs) == 0 {
+		return view, nil
+	}
+
+	filter["_id"] = bson.M{"$in": collection.ArtworkIDs}
+	cursor, err := database.Collection("artworks").Find(ctx, filter)
+	if err != nil {
+		return view, err
+	}
+	defer cursor.Close(ctx)
+
+	var artworks []models.Artwork
+	if err := cursor.All(ctx, &artworks); err != nil {
+		return view, err
+	}
+
+	byID := make(map[primitive.ObjectID]models.Artwork, len(artworks))
+	for _, a := range artworks {
+		byID[a.ID] = a
+	}
+	return assembleCollection(collection, byID), nil
+}
+
+// assembleCollection builds the view of collection from artworks already
+// loaded and filtered by the caller, keyed by id.
+func assembleCollection(collection models.Collection, byID map[primitive.ObjectID]models.Artwork) collectionView {
+	view := collectionView{Collection: collection, Artworks: []models.Artwork{}}
+	for _, id := range collection.ArtworkIDs {
+		if a, ok := byID[id]; ok {
+			view.Artworks = append(view.Artworks, a)
+		}
+	}
+	fillAltText(view.Artworks)
+
+	if collection.CoverArtworkID != nil && !containsObjectID(artworkIDs(view.Artworks), *collection.CoverArtworkID) {
+		view.CoverArtworkID = nil
+	}
+	view.CoverURL = collectionCoverURL(view)
+	view.ArtworkIDs = artworkIDs(view.Artworks)
+	view.ArtworkCount = len(view.Artworks)
+	return view
+}
+
+func CreateCollection(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Title       string   `json:"title" binding:"required"`
+		Description string   `json:"description"`
+		Visibility  string   `json:"visibility"`
+		ArtworkIDs  []string `json:"artworkIds"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title cannot be empty"})
+		return
+	}
+	if len(input.Description) > maxCollectionDescription {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "description too long"})
+		return
+	}
+	if input.Visibility == "" {
+		input.Visibility = models.VisibilityPublic
+	}
+	if !models.IsValidCollectionVisibility(input.Visibility) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "visibility must be one of private, unlisted, public"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ids, err := parseCollectionArtworks(ctx, userID, input.ArtworkIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slug, err := uniqueCollectionSlug(ctx, userID, title, primitive.NilObjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create collection"})
+		return
+	}
+
+	now := time.Now()
+	collection := models.Collection{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Title:       title,
+		Slug:        slug,
+		Description: strings.TrimSpace(input.Description),
+		Visibility:  input.Visibility,
+		ArtworkIDs:  ids,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if _, err := database.Collection("collections").InsertOne(ctx, collection); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "a collection with this title already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create collection"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+func GetMyCollections(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.Collection("collections").Find(
+		ctx,
+		bson.M{"userId": userID},
+		options.Find().SetSort(bson.M{"createdAt": -1}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch collections"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	collections := []models.Collection{}
+	if err := cursor.All(ctx, &collections); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse collections"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":       len(collections),
+		"collections": collections,
+	})
+}
+
+// GetMyCollection returns one of the user's collections with every artwork
+// in it, whatever the artworks' visibility.
+func GetMyCollection(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, ok := findOwnedCollection(c, ctx)
+	if !ok {
+		return
+	}
+
+	view, err := resolveCollection(ctx, *collection, activeArtworkFilter(nil))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// UpdateCollection edits a collection's details. Only the fields present in
+// the body are changed; renaming also updates the slug.
+func UpdateCollection(c *gin.Context) {
+	var input struct {
+		Title          *string `json:"title"`
+		Description    *string `json:"description"`
+		Visibility     *string `json:"visibility"`
+		CoverArtworkID *string `json:"coverArtworkId"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, ok := findOwnedCollection(c, ctx)
+	if !ok {
+		return
+	}
+
+	set := bson.M{}
+	unset := bson.M{}
+
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "title cannot be empty"})
+			return
+		}
+		if title != collection.Title {
+			slug, err := uniqueCollectionSlug(ctx, collection.UserID, title, collection.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update collection"})
+				return
+			}
+			set["title"] = title
+			set["slug"] = slug
+		}
+	}
+	if input.Description != nil {
+		if len(*input.Description) > maxCollectionDescription {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "description too long"})
+			return
+		}
+		set["description"] = strings.TrimSpace(*input.Description)
+	}
+	if input.Visibility != nil {
+		if !models.IsValidCollectionVisibility(*input.Visibility) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "visibility must be one of private, unlisted, public"})
+			return
+		}
+		set["visibility"] = *input.Visibility
+	}
+	if input.CoverArtworkID != nil {
+		if *input.CoverArtworkID == "" {
+			unset["coverArtworkId"] = ""
+		} else {
+			coverID, err := primitive.ObjectIDFromHex(*input.CoverArtworkID)
+			if err != nil || !containsObjectID(collection.ArtworkIDs, coverID) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "cover must be an artwork in the collection"})
+				return
+			}
+			set["coverArtworkId"] = coverID
+		}
+	}
+
+	set["updatedAt"] = time.Now()
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	var updated models.Collection
+	err := database.Collection("collections").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": collection.ID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "a collection with this title already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// SetCollectionArtworks replaces the collection's contents with artworkIds,
+// in the given order.
+func SetCollectionArtworks(c *gin.Context) {
+	var input struct {
+		ArtworkIDs []string `json:"artworkIds" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, ok := findOwnedCollection(c, ctx)
+	if !ok {
+		return
+	}
+
+	ids, err := parseCollectionArtworks(ctx, collection.UserID, input.ArtworkIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"artworkIds": ids, "updatedAt": time.Now()}}
+	if collection.CoverArtworkID != nil && !containsObjectID(ids, *collection.CoverArtworkID) {
+		update["$unset"] = bson.M{"coverArtworkId": ""}
+	}
+
+	var updated models.Collection
+	err = database.Collection("collections").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": collection.ID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// AddCollectionArtwork appends one artwork to the end of a collection.
+func AddCollectionArtwork(c *gin.Context) {
+	var input struct {
+		ArtworkID string `json:"artworkId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, ok := findOwnedCollection(c, ctx)
+	if !ok {
+		return
+	}
+
+	ids, err := parseCollectionArtworks(ctx, collection.UserID, []string{input.ArtworkID})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The guards keep duplicates and concurrent adds past the cap out.
+	res, err := database.Collection("collections").UpdateOne(ctx, bson.M{
+		"_id":        collection.ID,
+		"artworkIds": bson.M{"$ne": ids[0]},
+		fmt.Sprintf("artworkIds.%d", models.MaxArtworksPerCollection-1): bson.M{"$exists": false},
+	}, bson.M{
+		"$push": bson.M{"artworkIds": ids[0]},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add artwork"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "artwork is already in the collection or the collection is full"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "artwork added to collection"})
+}
+
+func RemoveCollectionArtwork(c *gin.Context) {
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("artworkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, ok := findOwnedCollection(c, ctx)
+	if !ok {
+		return
+	}
+	if !containsObjectID(collection.ArtworkIDs, artworkID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork is not in the collection"})
+		return
+	}
+
+	update := bson.M{
+		"$pull": bson.M{"artworkIds": artworkID},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	if collection.CoverArtworkID != nil && *collection.CoverArtworkID == artworkID {
+		update["$unset"] = bson.M{"coverArtworkId": ""}
+	}
+
+	if _, err := database.Collection("collections").UpdateOne(ctx, bson.M{"_id": collection.ID}, update); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove artwork"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "artwork removed from collection"})
+}
+
+// DeleteCollection removes the collection only; its artworks are untouched.
+func DeleteCollection(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, ok := findOwnedCollection(c, ctx)
+	if !ok {
+		return
+	}
+
+	if _, err := database.Collection("collections").DeleteOne(ctx, bson.M{"_id": collection.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "collection deleted"})
+}
+
+// GetPublicCollections lists an artist's public collections.
+func GetPublicCollections(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := findArtistByName(ctx, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artist not found"})
+		return
+	}
+
+	cursor, err := database.Collection("collections").Find(
+		ctx,
+		bson.M{"userId": user.ID, "visibility": models.VisibilityPublic},
+		options.Find().SetSort(bson.M{"createdAt": -1}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch collections"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var collections []models.Collection
+	if err := cursor.All(ctx, &collections); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse collections"})
+		return
+	}
+
+	// Every collection's artworks are loaded with one query rather than one
+	// per collection.
+	preference := viewerMaturePreference(c, ctx)
+	var ids []primitive.ObjectID
+	for _, col := range collections {
+		ids = append(ids, col.ArtworkIDs...)
+	}
+	byID := map[primitive.ObjectID]models.Artwork{}
+	if len(ids) > 0 {
+		cursor, err := database.Collection("artworks").Find(ctx, matureContentFilter(publicArtworkFilter(bson.M{"_id": bson.M{"$in": ids}}), preference))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+			return
+		}
+		var artworks []models.Artwork
+		if err := cursor.All(ctx, &artworks); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+			return
+		}
+		for _, a := range artworks {
+			byID[a.ID] = a
+		}
+	}
+
+	views := make([]collectionView, 0, len(collections))
+	for _, col := range collections {
+		view := assembleCollection(col, byID)
+		gateCollection(&view, preference)
+		// Listings only need the cover and count, not every artwork.
+		view.Artworks = nil
+		views = append(views, view)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile":     gin.H{"name": user.FullName},
+		"count":       len(views),
+		"collections": views,
+	})
+}
+
+// GetPublicCollection returns one collection by slug. Public collections
+// show the artist's public artworks; unlisted ones, reachable only by link,
+// also show unlisted artworks.
+func GetPublicCollection(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := findArtistByName(ctx, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artist not found"})
+		return
+	}
+
+	var collection models.Collection
+	err = database.Collection("collections").FindOne(ctx, bson.M{
+		"userId":     user.ID,
+		"slug":       c.Param("slug"),
+		"visibility": bson.M{"$in": []string{models.VisibilityPublic, models.VisibilityUnlisted}},
+	}).Decode(&collection)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+		return
+	}
+
+	filter := publicArtworkFilter(nil)
+	if collection.Visibility == models.VisibilityUnlisted {
+		filter = linkableArtworkFilter(nil)
+	}
+	preference := viewerMaturePreference(c, ctx)
+
+	view, err := resolveCollection(ctx, collection, matureContentFilter(filter, preference))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	gateCollection(&view, preference)
+	markLikedByMe(c, ctx, view.Artworks)
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile":    gin.H{"name": user.FullName},
+		"collection": view,
+	})
+}
+
+// collectionCoverURL is the URL of the chosen cover artwork, or of the first
+// artwork when none is chosen.
+func collectionCoverURL(view collectionView) string {
+	for _, a := range view.Artworks {
+		if view.CoverArtworkID == nil || a.ID == *view.CoverArtworkID {
+			return a.URL
+		}
+	}
+	return ""
+}
+
+// gateCollection blurs mature artworks for the viewer, including the cover.
+func gateCollection(view *collectionView, preference string) {
+	gateArtworks(view.Artworks, preference)
+	view.CoverURL = collectionCoverURL(*view)
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func artworkIDs(artworks []models.Artwork) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, len(artworks))
+	for i, a := range artworks {
+		ids[i] = a.ID
+	}
+	return ids
+}
@@ -0,0 +1,463 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	errCommentsDisabled  = errors.New("comments are disabled on this artwork")
+	errCommentTooDeep    = errors.New("replies cannot be nested any deeper")
+	errCommentNotAuthor  = errors.New("only the author can edit this comment")
+	errCommentNotAllowed = errors.New("you cannot moderate this comment")
+)
+
+// commentView is a comment as served to clients, with its author attached.
+// UserID shadows the comment's own so it can be left out for deleted
+// comments, which must not say who wrote them.
+type commentView struct {
+	models.Comment
+	UserID *primitive.ObjectID `json:"userId,omitempty"`
+	Author *artistSummary      `json:"author,omitempty"`
+}
+
+func newCommentView(cm models.Comment) commentView {
+	view := commentView{Comment: cm}
+	if !cm.Deleted {
+		view.UserID = &cm.UserID
+	}
+	return view
+}
+
+func commentViews(ctx context.Context, comments []models.Comment) ([]commentView, error) {
+	ids := make([]primitive.ObjectID, len(comments))
+	for i, cm := range comments {
+		ids[i] = cm.UserID
+	}
+	authors, err := findArtistSummaries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]artistSummary, len(authors))
+	for _, a := range authors {
+		byID[a.ID] = a
+	}
+
+	views := make([]commentView, len(comments))
+	for i, cm := range comments {
+		views[i] = newCommentView(cm)
+		if a, ok := byID[cm.UserID]; ok && !cm.Deleted {
+			views[i].Author = &a
+		}
+	}
+	return views, nil
+}
+
+// findCommentableArtwork loads the public artwork named by :id, writing the
+// error response itself when there is none.
+func findCommentableArtwork(c *gin.Context, ctx context.Context) (models.Artwork, bool) {
+	var artwork models.Artwork
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork id"})
+		return artwork, false
+	}
+
+	err = database.Collection("artworks").FindOne(
+		ctx,
+		publicArtworkFilter(bson.M{"_id": artworkID}),
+		options.FindOne().SetProjection(bson.M{"userId": 1, "title": 1, "commentsDisabled": 1}),
+	).Decode(&artwork)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found"})
+		return artwork, false
+	}
+	return artwork, true
+}
+
+// findComment loads the comment named by :id and the id of the artist who
+// owns its artwork, writing the error response itself when there is none.
+func findComment(c *gin.Context, ctx context.Context) (models.Comment, primitive.ObjectID, bool) {
+	var comment models.Comment
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment id"})
+		return comment, primitive.NilObjectID, false
+	}
+
+	err = database.Collection("comments").FindOne(ctx, bson.M{"_id": commentID, "deleted": bson.M{"$ne": true}}).Decode(&comment)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return comment, primitive.NilObjectID, false
+	}
+
+	var artwork models.Artwork
+	err = database.Collection("artworks").FindOne(
+		ctx,
+		activeArtworkFilter(bson.M{"_id": comment.ArtworkID}),
+		options.FindOne().SetProjection(bson.M{"userId": 1}),
+	).Decode(&artwork)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return comment, primitive.NilObjectID, false
+	}
+	return comment, artwork.UserID, true
+}
+
+// removeComment deletes a comment. One with replies is cleared instead so
+// the thread stays readable, and cleared comments whose last reply goes are
+// removed in turn.
+func removeComment(ctx context.Context, comment models.Comment) error {
+	comments := database.Collection("comments")
+	if comment.Replies > 0 {
+		_, err := comments.UpdateOne(
+			ctx,
+			bson.M{"_id": comment.ID},
+			bson.M{
+				"$set":   bson.M{"deleted": true, "body": ""},
+				"$unset": bson.M{"userId": "", "editedAt": "", "hidden": ""},
+			},
+		)
+		return err
+	}
+
+	if _, err := comments.DeleteOne(ctx, bson.M{"_id": comment.ID}); err != nil {
+		return err
+	}
+	if _, err := database.Collection("notifications").DeleteMany(ctx, bson.M{"commentId": comment.ID}); err != nil {
+		return err
+	}
+	for parentID := comment.ParentID; parentID != nil; {
+		var parent models.Comment
+		err := comments.FindOneAndUpdate(
+			ctx,
+			bson.M{"_id": *parentID},
+			bson.M{"$inc": bson.M{"replies": -1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&parent)
+		if err != nil || !parent.Deleted || parent.Replies > 0 {
+			return nil
+		}
+		if _, err := comments.DeleteOne(ctx, bson.M{"_id": parent.ID}); err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// GetArtworkComments handles GET /artworks/:id/comments. Without ?parentId=
+// it pages through top-level comments, newest first; with it, through that
+// comment's replies, oldest first. Hidden comments are only listed for the
+// artwork's owner.
+func GetArtworkComments(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	artwork, ok := findCommentableArtwork(c, ctx)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{"artworkId": artwork.ID, "parentId": nil}
+	defaultSort := sortNewest
+	if parent := c.Query("parentId"); parent != "" {
+		parentID, err := primitive.ObjectIDFromHex(parent)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid parent id"})
+			return
+		}
+		filter["parentId"] = parentID
+		defaultSort = sortOldest
+	}
+	isOwner := false
+	if viewer, ok := optionalUserID(c); ok {
+		isOwner = viewer == artwork.UserID
+	}
+	if !isOwner {
+		filter["hidden"] = bson.M{"$ne": true}
+	}
+
+	page, err := parsePageRequest(c, defaultSort, sortNewest, sortOldest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection := database.Collection("comments")
+	cursor, err := collection.Find(
+		ctx,
+		page.query(filter),
+		options.Find().SetSort(page.sortOrder()).SetLimit(int64(page.Limit+1)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch comments"})
+		return
+	}
+	var comments []models.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse comments"})
+		return
+	}
+
+	next := ""
+	if len(comments) > page.Limit {
+		comments = comments[:page.Limit]
+		last := comments[len(comments)-1]
+		next = encodeCursor(pageCursor{Sort: page.Sort, CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	views, err := commentViews(ctx, comments)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch comments"})
+		return
+	}
+	total, _ := collection.CountDocuments(ctx, filter)
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments":         views,
+		"count":            len(views),
+		"total":            total,
+		"nextCursor":       next,
+		"hasMore":          next != "",
+		"commentsDisabled": artwork.CommentsDisabled,
+	})
+}
+
+// CreateComment handles POST /artworks/:id/comments. A parentId makes the
+// comment a reply.
+func CreateComment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Body     string `json:"body" binding:"required"`
+		ParentID string `json:"parentId"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	body, err := utils.SanitizeComment(input.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	artwork, ok := findCommentableArtwork(c, ctx)
+	if !ok {
+		return
+	}
+	if artwork.CommentsDisabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCommentsDisabled.Error()})
+		return
+	}
+
+	collection := database.Collection("comments")
+	comment := models.Comment{
+		ID:        primitive.NewObjectID(),
+		ArtworkID: artwork.ID,
+		UserID:    userID,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+
+	var parentAuthor primitive.ObjectID
+	if input.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(input.ParentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid parent id"})
+			return
+		}
+		var parent models.Comment
+		err = collection.FindOne(ctx, bson.M{
+			"_id":       parentID,
+			"artworkId": artwork.ID,
+			"deleted":   bson.M{"$ne": true},
+			"hidden":    bson.M{"$ne": true},
+		}).Decode(&parent)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "parent comment not found"})
+			return
+		}
+		if parent.Depth >= models.MaxCommentDepth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errCommentTooDeep.Error()})
+			return
+		}
+		comment.ParentID = &parent.ID
+		comment.Depth = parent.Depth + 1
+		parentAuthor = parent.UserID
+	}
+
+	if _, err := collection.InsertOne(ctx, comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save comment"})
+		return
+	}
+	if comment.ParentID != nil {
+		collection.UpdateOne(ctx, bson.M{"_id": *comment.ParentID}, bson.M{"$inc": bson.M{"replies": 1}})
+	}
+
+	// The artist hears about every comment on their work unless it is a
+	// reply to them, which they hear about as a reply.
+	if comment.ParentID != nil {
+		notify(ctx, models.Notification{
+			UserID:       parentAuthor,
+			Type:         models.NotificationReply,
+			ActorID:      &comment.UserID,
+			ArtworkID:    &comment.ArtworkID,
+			CommentID:    &comment.ID,
+			ArtworkTitle: artwork.Title,
+		})
+	}
+	if comment.ParentID == nil || parentAuthor != artwork.UserID {
+		notify(ctx, models.Notification{
+			UserID:       artwork.UserID,
+			Type:         models.NotificationComment,
+			ActorID:      &comment.UserID,
+			ArtworkID:    &comment.ArtworkID,
+			CommentID:    &comment.ID,
+			ArtworkTitle: artwork.Title,
+		})
+	}
+
+	views, err := commentViews(ctx, []models.Comment{comment})
+	if err != nil {
+		c.JSON(http.StatusCreated, gin.H{"comment": newCommentView(comment)})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"comment": views[0]})
+}
+
+// UpdateComment handles PATCH /comments/:id. Only the author can edit.
+func UpdateComment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	body, err := utils.SanitizeComment(input.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	comment, _, ok := findComment(c, ctx)
+	if !ok {
+		return
+	}
+	if comment.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCommentNotAuthor.Error()})
+		return
+	}
+
+	now := time.Now()
+	_, err = database.Collection("comments").UpdateOne(
+		ctx,
+		bson.M{"_id": comment.ID},
+		bson.M{"$set": bson.M{"body": body, "editedAt": now}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update comment"})
+		return
+	}
+	comment.Body = body
+	comment.EditedAt = &now
+
+	views, err := commentViews(ctx, []models.Comment{comment})
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"comment": newCommentView(comment)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"comment": views[0]})
+}
+
+// DeleteComment handles DELETE /comments/:id, by the author or by the owner
+// of the artwork.
+func DeleteComment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	comment, ownerID, ok := findComment(c, ctx)
+	if !ok {
+		return
+	}
+	if comment.UserID != userID && ownerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCommentNotAllowed.Error()})
+		return
+	}
+
+	if err := removeComment(ctx, comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete comment"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted"})
+}
+
+// SetCommentHidden handles PUT /comments/:id/hidden. Only the owner of the
+// artwork can hide or unhide its comments.
+func SetCommentHidden(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Hidden *bool `json:"hidden" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	comment, ownerID, ok := findComment(c, ctx)
+	if !ok {
+		return
+	}
+	if ownerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCommentNotAllowed.Error()})
+		return
+	}
+
+	update := bson.M{"$unset": bson.M{"hidden": ""}}
+	if *input.Hidden {
+		update = bson.M{"$set": bson.M{"hidden": true}}
+	}
+	if _, err := database.Collection("comments").UpdateOne(ctx, bson.M{"_id": comment.ID}, update); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update comment"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "comment updated", "hidden": *input.Hidden})
+}
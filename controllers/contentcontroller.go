@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	errInvalidContentRating  = errors.New("contentRating must be one of general, mature, explicit")
+	errInvalidContentWarning = errors.New("invalid content warning")
+)
+
+// normalizeContentWarnings slugifies and dedupes warnings and checks them
+// against models.ContentWarnings.
+func normalizeContentWarnings(warnings []string) ([]string, error) {
+	normalized := utils.NormalizeTags(warnings)
+	for _, w := range normalized {
+		if _, ok := models.ContentWarnings[w]; !ok {
+			return nil, errInvalidContentWarning
+		}
+	}
+	return normalized, nil
+}
+
+func validateContentRating(rating string) error {
+	if rating != "" && !models.IsValidContentRating(rating) {
+		return errInvalidContentRating
+	}
+	return nil
+}
+
+// viewerMaturePreference returns how the current viewer wants mature
+// artworks handled. Anonymous viewers and users who never opted in get
+// MatureHide.
+func viewerMaturePreference(c *gin.Context, ctx context.Context) string {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		return models.MatureHide
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		return models.MatureHide
+	}
+
+	var user models.User
+	err = database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"mature_content": 1}),
+	).Decode(&user)
+	if err != nil || !models.IsValidMaturePreference(user.MatureContent) {
+		return models.MatureHide
+	}
+	return user.MatureContent
+}
+
+// matureContentFilter narrows a listing filter for viewers who hide mature
+// content.
+func matureContentFilter(filter bson.M, preference string) bson.M {
+	if preference == models.MatureHide {
+		filter["contentRating"] = bson.M{"$nin": []string{models.RatingMature, models.RatingExplicit}}
+	}
+	return filter
+}
+
+// gateArtwork marks a mature artwork blurred unless the viewer has chosen
+// to see mature content, and swaps every link to its files for the stored
+// blurred previews so the response can't be used to get around the gate.
+// Files whose preview hasn't been made yet are left without a link.
+func gateArtwork(artwork *models.Artwork, preference string) {
+	if !models.IsMatureRating(artwork.ContentRating) || preference == models.MatureShow {
+		return
+	}
+
+	artwork.Blurred = true
+	artwork.URL = artwork.BlurredURL
+	artwork.PublicID = ""
+	if artwork.PosterURL != "" {
+		artwork.PosterURL = artwork.BlurredURL
+	}
+	for i := range artwork.Media {
+		m := &artwork.Media[i]
+		m.URL = m.BlurredURL
+		m.PublicID = ""
+		if m.PosterURL != "" {
+			m.PosterURL = m.BlurredURL
+		}
+	}
+}
+
+func gateArtworks(artworks []models.Artwork, preference string) {
+	for i := range artworks {
+		gateArtwork(&artworks[i], preference)
+	}
+}
+
+// ensureBlurredPreviews makes the blurred previews a mature artwork is
+// missing and records them, keeping the top-level cover fields in step.
+// A preview made for an item that was removed in the meantime is deleted
+// again.
+func ensureBlurredPreviews(ctx context.Context, artwork models.Artwork) error {
+	if !models.IsMatureRating(artwork.ContentRating) {
+		return nil
+	}
+	artworks := database.Collection("artworks")
+
+	// Artworks created before multi-media support only have the top-level
+	// asset.
+	if len(artwork.Media) == 0 {
+		if artwork.BlurredPublicID != "" {
+			return nil
+		}
+		item := models.MediaItem{MediaType: artwork.MediaType, URL: artwork.URL, PosterURL: artwork.PosterURL}
+		if err := storeBlurredPreview(ctx, &item); err != nil || item.BlurredPublicID == "" {
+			return err
+		}
+		res, err := artworks.UpdateOne(
+			ctx,
+			bson.M{"_id": artwork.ID, "publicId": artwork.PublicID, "media": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"blurredUrl": item.BlurredURL, "blurredPublicId": item.BlurredPublicID}},
+		)
+		if err != nil || res.MatchedCount == 0 {
+			destroyAsset(context.Background(), item.BlurredPublicID, "")
+		}
+		return err
+	}
+
+	var errs []error
+	for i := range artwork.Media {
+		item := &artwork.Media[i]
+		if item.BlurredPublicID != "" {
+			continue
+		}
+		if err := storeBlurredPreview(ctx, item); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if item.BlurredPublicID == "" {
+			continue
+		}
+		res, err := artworks.UpdateOne(
+			ctx,
+			bson.M{"_id": artwork.ID, "media._id": item.ID},
+			bson.M{"$set": bson.M{"media.$.blurredUrl": item.BlurredURL, "media.$.blurredPublicId": item.BlurredPublicID}},
+		)
+		if err != nil || res.MatchedCount == 0 {
+			destroyAsset(context.Background(), item.BlurredPublicID, "")
+			item.BlurredPublicID = ""
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if cover, i := findMediaItem(&artwork, artwork.CoverMediaID); i >= 0 && cover.BlurredPublicID != "" && cover.BlurredPublicID != artwork.BlurredPublicID {
+		_, err := artworks.UpdateOne(
+			ctx,
+			bson.M{"_id": artwork.ID, "coverMediaId": cover.ID},
+			bson.M{"$set": bson.M{"blurredUrl": cover.BlurredURL, "blurredPublicId": cover.BlurredPublicID}},
+		)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GenerateBlurredPreviews makes the blurred previews that mature artworks
+// are missing every five minutes: older artworks, artworks whose rating was
+// raised and uploads whose preview failed the first time.
+func GenerateBlurredPreviews() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		cursor, err := database.Collection("artworks").Find(
+			ctx,
+			activeArtworkFilter(bson.M{
+				"contentRating": bson.M{"$in": []string{models.RatingMature, models.RatingExplicit}},
+				"$or": bson.A{
+					bson.M{"blurredPublicId": bson.M{"$exists": false}},
+					bson.M{"media": bson.M{"$elemMatch": bson.M{"blurredPublicId": bson.M{"$exists": false}}}},
+				},
+			}),
+			options.Find().SetLimit(50),
+		)
+		if err != nil {
+			fmt.Println("Blurred preview lookup failed:", err)
+		} else {
+			var artworks []models.Artwork
+			if err := cursor.All(ctx, &artworks); err != nil {
+				fmt.Println("Blurred preview lookup failed:", err)
+			}
+			for _, artwork := range artworks {
+				if err := ensureBlurredPreviews(ctx, artwork); err != nil {
+					fmt.Println("Blurred preview failed for", artwork.ID.Hex(), ":", err)
+				}
+			}
+		}
+		cancel()
+
+		time.Sleep(5 * time.Minute)
+	}
+}
+
+func GetContentPreferences(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"mature_content": 1}),
+	).Decode(&user)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	preference := user.MatureContent
+	if !models.IsValidMaturePreference(preference) {
+		preference = models.MatureHide
+	}
+	c.JSON(http.StatusOK, gin.H{"matureContent": preference})
+}
+
+// UpdateContentPreferences sets whether mature artworks are hidden, shown
+// blurred or shown in full to the user.
+func UpdateContentPreferences(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		MatureContent string `json:"matureContent" binding:"required,oneof=hide blur show"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := database.Collection("users").UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"mature_content": input.MatureContent, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update preferences"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matureContent": input.MatureContent})
+}
+
+// splitContentWarnings reads warnings sent as one comma-separated form value.
+func splitContentWarnings(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	return normalizeContentWarnings(strings.Split(value, ","))
+}
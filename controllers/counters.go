@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nerokome/artfolio-backend/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const recountInterval = 6 * time.Hour
+
+// engagementCounter is a stored count kept up with $inc: field on each
+// document in collection holds how many documents in source point at it
+// through key.
+type engagementCounter struct {
+	collection string
+	field      string
+	source     string
+	key        string
+}
+
+var engagementCounters = []engagementCounter{
+	{collection: "artworks", field: "likes", source: "likes", key: "artworkId"},
+	{collection: "users", field: "followers_count", source: "follows", key: "followingId"},
+	{collection: "users", field: "following_count", source: "follows", key: "followerId"},
+}
+
+// RecountEngagement recounts every engagementCounter from its source
+// documents every recountInterval, repairing counts left behind by
+// increments that failed.
+func RecountEngagement() {
+	for {
+		time.Sleep(recountInterval)
+
+		for _, counter := range engagementCounters {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			if err := recount(ctx, counter); err != nil {
+				fmt.Println("Recount of "+counter.collection+"."+counter.field+" failed:", err)
+			}
+			cancel()
+		}
+	}
+}
+
+type storedCount struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Count int                `bson:"n"`
+}
+
+func aggregateCounts(ctx context.Context, collection string, pipeline []bson.M) (map[primitive.ObjectID]int, error) {
+	cursor, err := database.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var rows []storedCount
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	counts := make(map[primitive.ObjectID]int, len(rows))
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, nil
+}
+
+// recount reads the stored values before counting, and each correction only
+// applies while the stored value is still the one read, so an increment that
+// lands mid-recount is never overwritten.
+func recount(ctx context.Context, counter engagementCounter) error {
+	stored, err := aggregateCounts(ctx, counter.collection, []bson.M{
+		{"$project": bson.M{"n": bson.M{"$ifNull": bson.A{"$" + counter.field, 0}}}},
+	})
+	if err != nil {
+		return err
+	}
+	counted, err := aggregateCounts(ctx, counter.source, []bson.M{
+		{"$group": bson.M{"_id": "$" + counter.key, "n": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	collection := database.Collection(counter.collection)
+	var writes []mongo.WriteModel
+	flush := func() error {
+		if len(writes) == 0 {
+			return nil
+		}
+		_, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		writes = writes[:0]
+		return err
+	}
+
+	for id, n := range stored {
+		if counted[id] == n {
+			continue
+		}
+		var current interface{} = n
+		if n == 0 {
+			// Documents from before the counter existed have no field.
+			current = bson.M{"$in": bson.A{0, nil}}
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, counter.field: current}).
+			SetUpdate(bson.M{"$set": bson.M{counter.field: counted[id]}}))
+		if len(writes) == 500 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
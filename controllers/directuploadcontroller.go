@@ -0,0 +1,394 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/config"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/middleware"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	uploadIntentTTL        = time.Hour
+	uploadIntentProcessing = "processing"
+)
+
+// directUploadFormats is signed into every intent so Cloudinary itself
+// rejects anything that isn't an image we can serve.
+var directUploadFormats = []string{"jpg", "jpeg", "png", "gif", "webp", "avif"}
+
+// CreateUploadIntent returns signed form fields the client posts straight to
+// Cloudinary, so the file never passes through this server.
+func CreateUploadIntent(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Title       string `json:"title" binding:"required"`
+		AltText     string `json:"altText"`
+		ContentType string `json:"contentType" binding:"required"`
+		Size        int64  `json:"size" binding:"required"`
+		Visibility  string `json:"visibility"`
+		PublishAt   string `json:"publishAt"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if err := middleware.ValidateFile(input.Size, input.ContentType, middleware.ArtworkMaxUploadMB, middleware.ArtworkAllowedTypes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":        err.Error(),
+			"fileType":     input.ContentType,
+			"allowedTypes": middleware.ArtworkAllowedTypes,
+		})
+		return
+	}
+
+	altText := strings.TrimSpace(input.AltText)
+	if _, err := checkAltText(altText); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	visibility, publishAt, err := requestedVisibility(input.Visibility, input.PublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if config.Cloudinary == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cloudinary not initialized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	settings, err := loadWatermarkSettings(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	now := time.Now()
+	intent := models.UploadIntent{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		Title:        strings.TrimSpace(input.Title),
+		AltText:      altText,
+		DeliveryType: string(api.Upload),
+		ContentType:  input.ContentType,
+		MaxBytes:     middleware.ArtworkMaxUploadMB * 1024 * 1024,
+		Status:       models.UploadIntentPending,
+		ExpiresAt:    now.Add(uploadIntentTTL),
+		CreatedAt:    now,
+		Visibility:   visibility,
+		PublishAt:    publishAt,
+	}
+
+	folder := "artfolio"
+	// Watermarked artists upload the clean original privately; the public
+	// variant is derived from it on completion.
+	if utils.WatermarkTransformation(&settings) != "" {
+		folder = "artfolio/originals"
+		intent.DeliveryType = api.Authenticated
+	}
+	intent.PublicID = folder + "/" + intent.ID.Hex()
+
+	params := url.Values{}
+	params.Set("timestamp", strconv.FormatInt(now.Unix(), 10))
+	params.Set("public_id", intent.ID.Hex())
+	params.Set("folder", folder)
+	params.Set("allowed_formats", strings.Join(directUploadFormats, ","))
+	if intent.DeliveryType != string(api.Upload) {
+		params.Set("type", intent.DeliveryType)
+	}
+
+	signature, err := api.SignParameters(params, config.Cloudinary.Config.Cloud.APISecret)
+	if err != nil {
+		fmt.Println("Upload signing failed:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to sign upload"})
+		return
+	}
+
+	if _, err := database.Collection("upload_intents").InsertOne(ctx, intent); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload intent"})
+		return
+	}
+
+	fields := gin.H{
+		"api_key":   config.Cloudinary.Config.Cloud.APIKey,
+		"signature": signature,
+	}
+	for k := range params {
+		fields[k] = params.Get(k)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"intentId":  intent.ID.Hex(),
+		"method":    "POST",
+		"uploadUrl": "https://api.cloudinary.com/v1_1/" + config.Cloudinary.Config.Cloud.CloudName + "/image/upload",
+		"fields":    fields,
+		"maxBytes":  intent.MaxBytes,
+		"expiresAt": intent.ExpiresAt,
+	})
+}
+
+// CompleteUpload verifies the object the client stored for an intent and
+// creates the artwork record. Objects that break the intent's constraints
+// are deleted.
+func CompleteUpload(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		IntentID string `json:"intentId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	intentID, err := primitive.ObjectIDFromHex(input.IntentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid intent id"})
+		return
+	}
+
+	if config.Cloudinary == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cloudinary not initialized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	intents := database.Collection("upload_intents")
+
+	// Claim the intent so concurrent completions can't create two artworks.
+	// Expired intents can't be claimed even before cleanup removes them.
+	pending := bson.M{"_id": intentID, "userId": userID, "status": models.UploadIntentPending}
+	var intent models.UploadIntent
+	err = intents.FindOneAndUpdate(
+		ctx,
+		bson.M{"$and": bson.A{pending, bson.M{"expiresAt": bson.M{"$gt": time.Now()}}}},
+		bson.M{"$set": bson.M{"status": uploadIntentProcessing}},
+	).Decode(&intent)
+	if err != nil {
+		if n, _ := intents.CountDocuments(ctx, pending); n > 0 {
+			c.JSON(http.StatusGone, gin.H{"error": "upload intent expired"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload intent not found or already completed"})
+		return
+	}
+
+	release := func() {
+		intents.UpdateOne(context.Background(), bson.M{"_id": intent.ID}, bson.M{"$set": bson.M{"status": models.UploadIntentPending}})
+	}
+
+	// Pages has to be asked for; without it the count is always 0 and
+	// animated GIFs and WebPs read as stills.
+	asset, err := config.Cloudinary.Admin.Asset(ctx, admin.AssetParams{
+		AssetType:    api.Image,
+		DeliveryType: api.DeliveryType(intent.DeliveryType),
+		PublicID:     intent.PublicID,
+		Pages:        api.Bool(true),
+	})
+	if err != nil || asset.Error.Message != "" || asset.PublicID == "" {
+		release()
+		c.JSON(http.StatusNotFound, gin.H{"error": "uploaded file not found in storage"})
+		return
+	}
+
+	if int64(asset.Bytes) > intent.MaxBytes || !isDirectUploadFormat(asset.Format) {
+		destroyAsset(ctx, intent.PublicID, intent.DeliveryType)
+		intents.DeleteOne(ctx, bson.M{"_id": intent.ID})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    "uploaded file violates upload constraints",
+			"bytes":    asset.Bytes,
+			"format":   asset.Format,
+			"maxBytes": intent.MaxBytes,
+		})
+		return
+	}
+
+	stored := &storedMedia{
+		MediaType: models.MediaTypeImage,
+		URL:       asset.SecureURL,
+		PublicID:  asset.PublicID,
+		Width:     asset.Width,
+		Height:    asset.Height,
+	}
+	if asset.Pages > 1 {
+		stored.MediaType = models.MediaTypeAnimated
+	}
+	if intent.DeliveryType == api.Authenticated {
+		stored, err = publishWatermarkedVariant(ctx, intent)
+		if err != nil {
+			fmt.Println("Watermarked variant failed:", err)
+			release()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "upload failed"})
+			return
+		}
+	}
+
+	var imagePrint *utils.ImagePrint
+	if p, err := printStoredImage(ctx, intent.PublicID, api.DeliveryType(intent.DeliveryType)); err == nil {
+		imagePrint = &p
+	} else {
+		fmt.Println("Perceptual hash skipped:", err)
+	}
+
+	details := newArtworkDetails(intent.Title)
+	details.AltText = intent.AltText
+	if intent.Visibility != "" {
+		details.Visibility = intent.Visibility
+		details.PublishAt = intent.PublishAt
+	}
+
+	artwork, warnings, err := saveArtwork(ctx, intent.UserID, details, stored, imagePrint)
+	if err != nil {
+		intents.DeleteOne(context.Background(), bson.M{"_id": intent.ID})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	intents.UpdateOne(ctx, bson.M{"_id": intent.ID}, bson.M{"$set": bson.M{
+		"status":    models.UploadIntentCompleted,
+		"artworkId": artwork.ID,
+	}})
+
+	response := gin.H{
+		"message": "upload successful",
+		"artwork": artwork,
+	}
+	if len(warnings) > 0 {
+		response["duplicateWarnings"] = warnings
+	}
+	if artwork.AltText == "" {
+		response["altTextWarning"] = missingAltTextWarning
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func isDirectUploadFormat(format string) bool {
+	for _, f := range directUploadFormats {
+		if strings.EqualFold(f, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// publishWatermarkedVariant derives the public, watermarked copy of a
+// privately stored original.
+func publishWatermarkedVariant(ctx context.Context, intent models.UploadIntent) (*storedMedia, error) {
+	originalURL, err := signedAssetURL(intent.PublicID, api.Authenticated, "")
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := loadWatermarkSettings(ctx, intent.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := uploadURLToCloudinary(ctx, originalURL, uploader.UploadParams{
+		Folder:         "artfolio",
+		Transformation: utils.WatermarkTransformation(&settings),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored := imageMedia(public)
+	stored.OriginalURL = originalURL
+	stored.OriginalPublicID = intent.PublicID
+	stored.Watermarked = true
+	return stored, nil
+}
+
+// printStoredImage computes the image print from a small derivative so
+// the full original never has to be downloaded.
+func printStoredImage(ctx context.Context, publicID string, deliveryType api.DeliveryType) (utils.ImagePrint, error) {
+	thumbURL, err := signedAssetURL(publicID, deliveryType, "c_limit,w_256/f_png")
+	if err != nil {
+		return utils.ImagePrint{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, thumbURL, nil)
+	if err != nil {
+		return utils.ImagePrint{}, err
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return utils.ImagePrint{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return utils.ImagePrint{}, fmt.Errorf("thumbnail fetch returned %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
+	if err != nil {
+		return utils.ImagePrint{}, err
+	}
+	return utils.ImagePrintBytes(data)
+}
+
+// CleanupUploadIntents deletes objects uploaded for intents that were never
+// completed, once they have been expired for a further uploadIntentTTL.
+// It sweeps every 15 minutes.
+func CleanupUploadIntents() {
+	for {
+		time.Sleep(15 * time.Minute)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		collection := database.Collection("upload_intents")
+		cursor, err := collection.Find(ctx, bson.M{
+			"status":    bson.M{"$ne": models.UploadIntentCompleted},
+			"expiresAt": bson.M{"$lt": time.Now().Add(-uploadIntentTTL)},
+		})
+		if err != nil {
+			fmt.Println("Upload intent cleanup failed:", err)
+			cancel()
+			continue
+		}
+
+		var expired []models.UploadIntent
+		cursor.All(ctx, &expired)
+
+		for _, intent := range expired {
+			if err := destroyAsset(ctx, intent.PublicID, intent.DeliveryType); err != nil {
+				fmt.Println("Failed to remove abandoned upload:", err)
+				continue
+			}
+			collection.DeleteOne(ctx, bson.M{"_id": intent.ID})
+		}
+		cancel()
+	}
+}
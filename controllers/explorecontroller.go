@@ -0,0 +1,348 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// An engagement event counts for half as much every trendingHalfLife and is
+// ignored after trendingLookback, by which point it is worth under 1%.
+const (
+	trendingHalfLife        = 48 * time.Hour
+	trendingLookback        = 14 * 24 * time.Hour
+	trendingRefreshInterval = 15 * time.Minute
+	maxTrendingArtworks     = 1000
+)
+
+// Diversity rules for the explore feed: each further piece by the same
+// artist counts for repeatPenalty as much, and an artist's pieces are kept
+// at least artistSpacing places apart whenever anyone else is left.
+const (
+	repeatPenalty = 0.5
+	artistSpacing = 3
+)
+
+// trendingSignal is an engagement source counted toward the trending score.
+// Its collection holds one document per event with artworkId and createdAt.
+type trendingSignal struct {
+	collection string
+	weight     float64
+}
+
+var trendingSignals = []trendingSignal{
+	{collection: "view_events", weight: 1},
+	{collection: "likes", weight: 3},
+}
+
+// RefreshTrendingScores rebuilds the trending_artworks collection behind the
+// explore feed on startup and every trendingRefreshInterval after.
+func RefreshTrendingScores() {
+	for {
+		if err := refreshTrending(); err != nil {
+			fmt.Println("Trending refresh failed:", err)
+		}
+		time.Sleep(trendingRefreshInterval)
+	}
+}
+
+// decayedScores sums every signal's recent events per artwork, each event
+// weighted by how long ago it happened.
+func decayedScores(ctx context.Context, now time.Time) (map[primitive.ObjectID]float64, error) {
+	scores := map[primitive.ObjectID]float64{}
+	halfLife := float64(trendingHalfLife.Milliseconds())
+
+	for _, signal := range trendingSignals {
+		cursor, err := database.Collection(signal.collection).Aggregate(ctx, []bson.M{
+			{"$match": bson.M{"createdAt": bson.M{"$gte": now.Add(-trendingLookback), "$lte": now}}},
+			{"$group": bson.M{
+				"_id": "$artworkId",
+				"score": bson.M{"$sum": bson.M{"$pow": bson.A{
+					0.5,
+					bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{now, "$createdAt"}}, halfLife}},
+				}}},
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var rows []struct {
+			ArtworkID primitive.ObjectID `bson:"_id"`
+			Score     float64            `bson:"score"`
+		}
+		if err := cursor.All(ctx, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			scores[row.ArtworkID] += signal.weight * row.Score
+		}
+	}
+	return scores, nil
+}
+
+// diversify re-ranks candidates, sorted by score, so no artist dominates the
+// feed: repeat pieces are discounted and spaced out.
+func diversify(candidates []models.TrendingArtwork) []models.TrendingArtwork {
+	ranked := make([]models.TrendingArtwork, 0, len(candidates))
+	used := make([]bool, len(candidates))
+	picked := map[primitive.ObjectID]int{}
+
+	recent := func(userID primitive.ObjectID) bool {
+		for i := len(ranked) - 1; i >= 0 && i >= len(ranked)-artistSpacing+1; i-- {
+			if ranked[i].UserID == userID {
+				return true
+			}
+		}
+		return false
+	}
+
+	for len(ranked) < len(candidates) {
+		best, fallback := -1, -1
+		bestScore, fallbackScore := -1.0, -1.0
+		for i, cand := range candidates {
+			if used[i] {
+				continue
+			}
+			adjusted := cand.Score * math.Pow(repeatPenalty, float64(picked[cand.UserID]))
+			if recent(cand.UserID) {
+				if adjusted > fallbackScore {
+					fallback, fallbackScore = i, adjusted
+				}
+				continue
+			}
+			if adjusted > bestScore {
+				best, bestScore = i, adjusted
+			}
+		}
+		if best == -1 {
+			best = fallback
+		}
+
+		used[best] = true
+		picked[candidates[best].UserID]++
+		ranked = append(ranked, candidates[best])
+	}
+	return ranked
+}
+
+func refreshTrending() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	scores, err := decayedScores(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+
+	var candidates []models.TrendingArtwork
+	if len(ids) > 0 {
+		cursor, err := database.Collection("artworks").Find(
+			ctx,
+			publicArtworkFilter(bson.M{"_id": bson.M{"$in": ids}}),
+			options.Find().SetProjection(bson.M{
+				"userId": 1, "tags": 1, "medium": 1, "category": 1, "contentRating": 1,
+			}),
+		)
+		if err != nil {
+			return err
+		}
+		var artworks []models.Artwork
+		if err := cursor.All(ctx, &artworks); err != nil {
+			return err
+		}
+
+		for _, a := range artworks {
+			candidates = append(candidates, models.TrendingArtwork{
+				ArtworkID:     a.ID,
+				UserID:        a.UserID,
+				Score:         scores[a.ID],
+				Tags:          a.Tags,
+				Medium:        a.Medium,
+				Category:      a.Category,
+				ContentRating: a.ContentRating,
+				ComputedAt:    now,
+			})
+		}
+	}
+
+	// Re-ranking is quadratic, so only the head of the list goes through it.
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	if len(candidates) > 2*maxTrendingArtworks {
+		candidates = candidates[:2*maxTrendingArtworks]
+	}
+	ranked := diversify(candidates)
+	if len(ranked) > maxTrendingArtworks {
+		ranked = ranked[:maxTrendingArtworks]
+	}
+
+	// The new ranking is written as a generation of its own and goes live
+	// in one write to the state document, so readers never see a mix of
+	// old and new ranks. The previous generation is kept for requests that
+	// read the state just before the switch.
+	generation := primitive.NewObjectID()
+	collection := database.Collection("trending_artworks")
+	if len(ranked) > 0 {
+		writes := make([]mongo.WriteModel, len(ranked))
+		for i := range ranked {
+			ranked[i].Rank = i + 1
+			ranked[i].Generation = generation
+			writes[i] = mongo.NewInsertOneModel().SetDocument(ranked[i])
+		}
+		if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			collection.DeleteMany(context.Background(), bson.M{"generation": generation})
+			return err
+		}
+	}
+
+	var previous models.TrendingState
+	err = database.Collection("trending_state").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": models.TrendingStateID},
+		bson.M{"$set": bson.M{"generation": generation, "computedAt": now}},
+		options.FindOneAndUpdate().SetUpsert(true),
+	).Decode(&previous)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		collection.DeleteMany(context.Background(), bson.M{"generation": generation})
+		return err
+	}
+
+	_, err = collection.DeleteMany(ctx, bson.M{"generation": bson.M{"$nin": bson.A{generation, previous.Generation}}})
+	return err
+}
+
+// currentTrendingGeneration returns the live generation of the explore feed,
+// or the zero id before the first refresh.
+func currentTrendingGeneration(ctx context.Context) (primitive.ObjectID, error) {
+	var state models.TrendingState
+	err := database.Collection("trending_state").FindOne(ctx, bson.M{"_id": models.TrendingStateID}).Decode(&state)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return primitive.NilObjectID, nil
+	}
+	return state.Generation, err
+}
+
+// exploreCursor encodes the generation a page came from with its last rank,
+// since ranks only mean something within one generation.
+func exploreCursor(generation primitive.ObjectID, rank int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(generation.Hex() + ":" + strconv.Itoa(rank)))
+}
+
+func parseExploreCursor(after string) (primitive.ObjectID, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(after)
+	if err != nil {
+		return primitive.NilObjectID, 0, errInvalidCursor
+	}
+	genHex, rankStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return primitive.NilObjectID, 0, errInvalidCursor
+	}
+	generation, err := primitive.ObjectIDFromHex(genHex)
+	if err != nil {
+		return primitive.NilObjectID, 0, errInvalidCursor
+	}
+	rank, err := strconv.Atoi(rankStr)
+	if err != nil || rank < 1 {
+		return primitive.NilObjectID, 0, errInvalidCursor
+	}
+	return generation, rank, nil
+}
+
+// GetExploreArtworks handles GET /artworks/explore: public artworks ranked by
+// recent engagement, with the same filters and envelope as the other
+// listings. A cursor from a ranking that has since been replaced restarts
+// at the first page, flagged with restarted so the client can reset its
+// list instead of mixing the two rankings.
+func GetExploreArtworks(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter, err := taxonomyFilter(c, bson.M{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	generation, err := currentTrendingGeneration(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	filter["generation"] = generation
+	restarted := false
+	if after := c.Query("after"); after != "" {
+		cursorGeneration, rank, err := parseExploreCursor(after)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if cursorGeneration == generation {
+			filter["rank"] = bson.M{"$gt": rank}
+		} else {
+			restarted = true
+		}
+	}
+	preference := viewerMaturePreference(c, ctx)
+	filter = matureContentFilter(filter, preference)
+	limit := boundedQueryInt(c, "limit", defaultPageSize, maxPageSize)
+
+	cursor, err := database.Collection("trending_artworks").Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.M{"rank": 1}).SetLimit(int64(limit+1)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	var entries []models.TrendingArtwork
+	if err := cursor.All(ctx, &entries); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse artworks"})
+		return
+	}
+
+	next := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next = exploreCursor(generation, entries[len(entries)-1].Rank)
+	}
+
+	ids := make([]primitive.ObjectID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ArtworkID
+	}
+	artworks, err := findPublicArtworksByID(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	fillAltText(artworks)
+	gateArtworks(artworks, preference)
+	markLikedByMe(c, ctx, artworks)
+
+	response := pageResponse(artworks, len(artworks), next)
+	if restarted {
+		response["restarted"] = true
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,320 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	errCannotFollowSelf    = errors.New("you cannot follow yourself")
+	errFollowLimitExceeded = errors.New("following limit reached")
+)
+
+// findTargetUser loads the user named by the :id path parameter, writing the
+// error response itself when there is none.
+func findTargetUser(c *gin.Context, ctx context.Context) (models.User, bool) {
+	var user models.User
+	targetID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return user, false
+	}
+
+	err = database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": targetID},
+		options.FindOne().SetProjection(bson.M{"password": 0}),
+	).Decode(&user)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return user, false
+	}
+	return user, true
+}
+
+// isFollowing reports whether the signed-in viewer, if any, follows
+// targetID.
+func isFollowing(c *gin.Context, ctx context.Context, targetID primitive.ObjectID) bool {
+	viewer, ok := optionalUserID(c)
+	if !ok {
+		return false
+	}
+	n, _ := database.Collection("follows").CountDocuments(
+		ctx,
+		bson.M{"followerId": viewer, "followingId": targetID},
+		options.Count().SetLimit(1),
+	)
+	return n > 0
+}
+
+// FollowUser handles POST /users/:id/follow. Following someone twice is not
+// an error; the counts only move when a new follow is created.
+func FollowUser(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	target, ok := findTargetUser(c, ctx)
+	if !ok {
+		return
+	}
+	if target.ID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errCannotFollowSelf.Error()})
+		return
+	}
+
+	users := database.Collection("users")
+	var follower models.User
+	err := users.FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"following_count": 1}),
+	).Decode(&follower)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if follower.FollowingCount >= models.MaxFollowing {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errFollowLimitExceeded.Error()})
+		return
+	}
+
+	result, err := database.Collection("follows").UpdateOne(
+		ctx,
+		bson.M{"followerId": userID, "followingId": target.ID},
+		bson.M{"$setOnInsert": models.Follow{
+			FollowerID:  userID,
+			FollowingID: target.ID,
+			CreatedAt:   time.Now(),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	// A concurrent follow of the same user loses the race on the unique
+	// index; the follow exists either way.
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to follow user"})
+		return
+	}
+
+	followers := target.FollowersCount
+	if result != nil && result.UpsertedCount == 1 {
+		// The follow is stored either way; counts that missed it are
+		// repaired by RecountEngagement.
+		updateFollowCounts(ctx, userID, target.ID, 1)
+		followers++
+		notify(ctx, models.Notification{
+			UserID:  target.ID,
+			Type:    models.NotificationFollow,
+			ActorID: &userID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "following",
+		"following":      true,
+		"followersCount": followers,
+	})
+}
+
+// updateFollowCounts moves both sides' counts of a follow by delta.
+func updateFollowCounts(ctx context.Context, followerID, followingID primitive.ObjectID, delta int) {
+	users := database.Collection("users")
+	if _, err := users.UpdateOne(ctx, bson.M{"_id": followerID}, bson.M{"$inc": bson.M{"following_count": delta}}); err != nil {
+		fmt.Println("Failed to update following count:", err)
+	}
+	if _, err := users.UpdateOne(ctx, bson.M{"_id": followingID}, bson.M{"$inc": bson.M{"followers_count": delta}}); err != nil {
+		fmt.Println("Failed to update followers count:", err)
+	}
+}
+
+// UnfollowUser handles DELETE /users/:id/follow.
+func UnfollowUser(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	target, ok := findTargetUser(c, ctx)
+	if !ok {
+		return
+	}
+
+	result, err := database.Collection("follows").DeleteOne(
+		ctx,
+		bson.M{"followerId": userID, "followingId": target.ID},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unfollow user"})
+		return
+	}
+
+	followers := target.FollowersCount
+	if result.DeletedCount == 1 {
+		updateFollowCounts(ctx, userID, target.ID, -1)
+		followers--
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "unfollowed",
+		"following":      false,
+		"followersCount": followers,
+	})
+}
+
+// listFollows pages through the follows where field is the :id user and
+// returns the users on the other end, most recent follow first.
+func listFollows(c *gin.Context, field string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	target, ok := findTargetUser(c, ctx)
+	if !ok {
+		return
+	}
+	page, err := parsePageRequest(c, sortNewest, sortNewest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cursor, err := database.Collection("follows").Find(
+		ctx,
+		page.query(bson.M{field: target.ID}),
+		options.Find().SetSort(page.sortOrder()).SetLimit(int64(page.Limit+1)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch users"})
+		return
+	}
+	var follows []models.Follow
+	if err := cursor.All(ctx, &follows); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse users"})
+		return
+	}
+
+	next := ""
+	if len(follows) > page.Limit {
+		follows = follows[:page.Limit]
+		last := follows[len(follows)-1]
+		next = encodeCursor(pageCursor{Sort: page.Sort, CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	ids := make([]primitive.ObjectID, len(follows))
+	for i, f := range follows {
+		ids[i] = f.FollowerID
+		if field == "followerId" {
+			ids[i] = f.FollowingID
+		}
+	}
+	users, err := findArtistSummaries(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch users"})
+		return
+	}
+
+	total := target.FollowersCount
+	if field == "followerId" {
+		total = target.FollowingCount
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"users":      users,
+		"count":      len(users),
+		"total":      total,
+		"nextCursor": next,
+		"hasMore":    next != "",
+	})
+}
+
+// GetFollowers handles GET /users/:id/followers.
+func GetFollowers(c *gin.Context) {
+	listFollows(c, "followingId")
+}
+
+// GetFollowing handles GET /users/:id/following.
+func GetFollowing(c *gin.Context) {
+	listFollows(c, "followerId")
+}
+
+// GetFeed handles GET /feed: newly published public artworks from the
+// artists the user follows. The feed is assembled when read rather than
+// pushed to followers on publish, so an artist with many followers costs
+// nothing extra; the read is one indexed query over at most
+// models.MaxFollowing artists.
+func GetFeed(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	page, err := parsePageRequest(c, sortPublished, sortPublished)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := listingFilter(c, publicArtworkFilter(nil))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cursor, err := database.Collection("follows").Find(
+		ctx,
+		bson.M{"followerId": userID},
+		options.Find().
+			SetProjection(bson.M{"followingId": 1}).
+			SetLimit(models.MaxFollowing),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch feed"})
+		return
+	}
+	var follows []models.Follow
+	if err := cursor.All(ctx, &follows); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch feed"})
+		return
+	}
+	if len(follows) == 0 {
+		c.JSON(http.StatusOK, pageResponse([]models.Artwork{}, 0, ""))
+		return
+	}
+
+	followed := make([]primitive.ObjectID, len(follows))
+	for i, f := range follows {
+		followed[i] = f.FollowingID
+	}
+	filter["userId"] = bson.M{"$in": followed}
+	preference := viewerMaturePreference(c, ctx)
+	filter = matureContentFilter(filter, preference)
+
+	artworks, next, err := findPage(ctx, database.Collection("artworks"), filter, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch feed"})
+		return
+	}
+	fillAltText(artworks)
+	gateArtworks(artworks, preference)
+	markLikedByMe(c, ctx, artworks)
+
+	c.JSON(http.StatusOK, pageResponse(artworks, len(artworks), next))
+}
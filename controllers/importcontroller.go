@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/middleware"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+)
+
+// ImportArtworkFromURL fetches a remote image on the artist's behalf and
+// creates an artwork from it, applying the same checks as a direct upload.
+func ImportArtworkFromURL(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		URL         string          `json:"url" binding:"required,url"`
+		Title       string          `json:"title" binding:"required"`
+		Tags        []string        `json:"tags"`
+		Medium      string          `json:"medium"`
+		Category    string          `json:"category"`
+		Description string          `json:"description"`
+		AltText     string          `json:"altText"`
+		License     *models.License `json:"license"`
+		Visibility  string          `json:"visibility"`
+		PublishAt   string          `json:"publishAt"`
+
+		ContentRating   string   `json:"contentRating"`
+		ContentWarnings []string `json:"contentWarnings"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if err := validateTaxonomy(input.Medium, input.Category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateDescription(input.Description); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.License != nil {
+		if err := normalizeLicense(input.License); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	visibility, publishAt, err := requestedVisibility(input.Visibility, input.PublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateContentRating(input.ContentRating); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	contentWarnings, err := normalizeContentWarnings(input.ContentWarnings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	altText := strings.TrimSpace(input.AltText)
+	altTextWarning, err := checkAltText(altText)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	result, err := utils.SafeFetch(ctx, input.URL, utils.SafeFetchOptions{
+		MaxBytes:     middleware.ArtworkMaxUploadMB * 1024 * 1024,
+		Timeout:      20 * time.Second,
+		MaxRedirects: 3,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrResponseTooLarge):
+			c.JSON(http.StatusBadRequest, gin.H{"error": middleware.ErrFileTooLarge.Error()})
+		case errors.Is(err, utils.ErrBlockedAddress),
+			errors.Is(err, utils.ErrUnsupportedURL),
+			errors.Is(err, utils.ErrTooManyRedirects):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			fmt.Println("URL import fetch failed:", err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch image from url"})
+		}
+		return
+	}
+
+	// The remote Content-Type header is not trusted; sniff the bytes.
+	contentType := http.DetectContentType(result.Data)
+	if err := middleware.ValidateFile(int64(len(result.Data)), contentType, middleware.ArtworkMaxUploadMB, middleware.ArtworkAllowedTypes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":        err.Error(),
+			"fileType":     contentType,
+			"allowedTypes": middleware.ArtworkAllowedTypes,
+		})
+		return
+	}
+
+	details := newArtworkDetails(strings.TrimSpace(input.Title))
+	details.Tags = utils.NormalizeTags(input.Tags)
+	details.Medium = input.Medium
+	details.Category = input.Category
+	details.Description = strings.TrimSpace(input.Description)
+	details.AltText = altText
+	details.License = input.License
+	details.ContentRating = input.ContentRating
+	details.ContentWarnings = contentWarnings
+	details.Visibility = visibility
+	details.PublishAt = publishAt
+
+	artwork, warnings, err := createArtwork(ctx, userID, details, result.Data)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"message":   "import successful",
+		"artwork":   artwork,
+		"sourceUrl": result.FinalURL,
+	}
+	if len(warnings) > 0 {
+		response["duplicateWarnings"] = warnings
+	}
+	if altTextWarning != "" {
+		response["altTextWarning"] = altTextWarning
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	errInvalidLicense      = errors.New("invalid license type")
+	errLicenseTextRequired = errors.New("customText is required for a custom license")
+	errLicenseTooLong      = errors.New("license text is too long")
+	errCopyrightYear       = errors.New("invalid copyright year")
+)
+
+// normalizeLicense trims a license sent by a client and checks it. License
+// types are matched case-insensitively, so "CC-BY" is "cc-by".
+func normalizeLicense(license *models.License) error {
+	license.Type = strings.ToLower(strings.TrimSpace(license.Type))
+	license.CustomText = strings.TrimSpace(license.CustomText)
+	license.CopyrightHolder = strings.TrimSpace(license.CopyrightHolder)
+	license.Attribution = strings.TrimSpace(license.Attribution)
+
+	if !models.IsValidLicenseType(license.Type) {
+		return errInvalidLicense
+	}
+	if license.Type == models.LicenseCustom && license.CustomText == "" {
+		return errLicenseTextRequired
+	}
+	if license.Type != models.LicenseCustom {
+		license.CustomText = ""
+	}
+	if utf8.RuneCountInString(license.CustomText) > 2000 ||
+		utf8.RuneCountInString(license.CopyrightHolder) > 200 ||
+		utf8.RuneCountInString(license.Attribution) > 500 {
+		return errLicenseTooLong
+	}
+	if license.CopyrightYear != 0 && (license.CopyrightYear < 1000 || license.CopyrightYear > time.Now().Year()+1) {
+		return errCopyrightYear
+	}
+	return nil
+}
+
+// resolveLicense completes the license of a new artwork. Without an explicit
+// license the artist's default applies, falling back to All Rights Reserved;
+// the holder and year default to the artist and the current year. A missing
+// user record falls back to those defaults; any other lookup error is
+// returned.
+func resolveLicense(ctx context.Context, userID primitive.ObjectID, license *models.License) (*models.License, error) {
+	var user models.User
+	err := database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"full_name": 1, "default_license": 1}),
+	).Decode(&user)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	var resolved models.License
+	switch {
+	case license != nil:
+		resolved = *license
+	case user.DefaultLicense != nil:
+		resolved = *user.DefaultLicense
+	default:
+		resolved = models.License{Type: models.LicenseAllRightsReserved}
+	}
+
+	if resolved.CopyrightHolder == "" {
+		resolved.CopyrightHolder = user.FullName
+	}
+	if resolved.CopyrightYear == 0 {
+		resolved.CopyrightYear = time.Now().Year()
+	}
+	return &resolved, nil
+}
+
+// GetDefaultLicense returns the license applied to the user's new uploads.
+func GetDefaultLicense(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"default_license": 1}),
+	).Decode(&user)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	license := models.License{Type: models.LicenseAllRightsReserved}
+	if user.DefaultLicense != nil {
+		license = *user.DefaultLicense
+	}
+	c.JSON(http.StatusOK, license)
+}
+
+// UpdateDefaultLicense sets the license applied to the user's new uploads.
+// Existing artworks keep their license.
+func UpdateDefaultLicense(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var license models.License
+	if err := c.ShouldBindJSON(&license); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if err := normalizeLicense(&license); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := database.Collection("users").UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"default_license": license, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update default license"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, license)
+}
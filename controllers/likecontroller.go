@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// markLikedByMe sets LikedByMe on the artworks the signed-in viewer likes.
+func markLikedByMe(c *gin.Context, ctx context.Context, artworks []models.Artwork) {
+	viewer, ok := optionalUserID(c)
+	if !ok || len(artworks) == 0 {
+		return
+	}
+
+	cursor, err := database.Collection("likes").Find(
+		ctx,
+		bson.M{"userId": viewer, "artworkId": bson.M{"$in": artworkIDs(artworks)}},
+		options.Find().SetProjection(bson.M{"artworkId": 1}),
+	)
+	if err != nil {
+		return
+	}
+	var likes []models.Like
+	if err := cursor.All(ctx, &likes); err != nil {
+		return
+	}
+
+	liked := make(map[primitive.ObjectID]bool, len(likes))
+	for _, l := range likes {
+		liked[l.ArtworkID] = true
+	}
+	for i := range artworks {
+		artworks[i].LikedByMe = liked[artworks[i].ID]
+	}
+}
+
+// LikeArtwork handles POST /artworks/:id/like. Liking an artwork twice is
+// not an error; the counter only moves when a new like is created.
+func LikeArtwork(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	artworks := database.Collection("artworks")
+	var artwork models.Artwork
+	err = artworks.FindOne(
+		ctx,
+		linkableArtworkFilter(bson.M{"_id": artworkID}),
+		options.FindOne().SetProjection(bson.M{"likes": 1}),
+	).Decode(&artwork)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found"})
+		return
+	}
+
+	result, err := database.Collection("likes").UpdateOne(
+		ctx,
+		bson.M{"artworkId": artworkID, "userId": userID},
+		bson.M{"$setOnInsert": models.Like{
+			ArtworkID: artworkID,
+			UserID:    userID,
+			CreatedAt: time.Now(),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	// A concurrent like by the same user loses the race on the unique index;
+	// the like exists either way.
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to like artwork"})
+		return
+	}
+
+	likes := artwork.Likes
+	if result != nil && result.UpsertedCount == 1 {
+		// The like is stored either way; a count that missed it is repaired
+		// by RecountEngagement.
+		_, err := artworks.UpdateOne(ctx, bson.M{"_id": artworkID}, bson.M{"$inc": bson.M{"likes": 1}})
+		if err != nil {
+			fmt.Println("Failed to update like count:", err)
+		}
+		likes++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "liked",
+		"liked":   true,
+		"likes":   likes,
+	})
+}
+
+// UnlikeArtwork handles DELETE /artworks/:id/like. It works on artworks that
+// have since been hidden, so a like can always be taken back.
+func UnlikeArtwork(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.Collection("likes").DeleteOne(ctx, bson.M{"artworkId": artworkID, "userId": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unlike artwork"})
+		return
+	}
+
+	artworks := database.Collection("artworks")
+	if result.DeletedCount == 1 {
+		_, err := artworks.UpdateOne(ctx, bson.M{"_id": artworkID}, bson.M{"$inc": bson.M{"likes": -1}})
+		if err != nil {
+			fmt.Println("Failed to update like count:", err)
+		}
+	}
+
+	var artwork models.Artwork
+	artworks.FindOne(
+		ctx,
+		bson.M{"_id": artworkID},
+		options.FindOne().SetProjection(bson.M{"likes": 1}),
+	).Decode(&artwork)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "unliked",
+		"liked":   false,
+		"likes":   artwork.Likes,
+	})
+}
+
+// GetLikedArtworks handles GET /users/:id/likes: the public artworks the
+// user has liked, most recently liked first.
+func GetLikedArtworks(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, ok := findTargetUser(c, ctx)
+	if !ok {
+		return
+	}
+	page, err := parsePageRequest(c, sortNewest, sortNewest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cursor, err := database.Collection("likes").Find(
+		ctx,
+		page.query(bson.M{"userId": user.ID}),
+		options.Find().SetSort(page.sortOrder()).SetLimit(int64(page.Limit+1)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	var likes []models.Like
+	if err := cursor.All(ctx, &likes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse artworks"})
+		return
+	}
+
+	next := ""
+	if len(likes) > page.Limit {
+		likes = likes[:page.Limit]
+		last := likes[len(likes)-1]
+		next = encodeCursor(pageCursor{Sort: page.Sort, CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	ids := make([]primitive.ObjectID, len(likes))
+	for i, l := range likes {
+		ids[i] = l.ArtworkID
+	}
+	// Likes of artworks that are no longer public drop out here, so a page
+	// can come back short.
+	found, err := findPublicArtworksByID(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+
+	preference := viewerMaturePreference(c, ctx)
+	artworks := found[:0]
+	for _, a := range found {
+		if preference != models.MatureHide || !models.IsMatureRating(a.ContentRating) {
+			artworks = append(artworks, a)
+		}
+	}
+	fillAltText(artworks)
+	gateArtworks(artworks, preference)
+	markLikedByMe(c, ctx, artworks)
+
+	c.JSON(http.StatusOK, pageResponse(artworks, len(artworks), next))
+}
@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/middleware"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var mediaKinds = map[string]bool{
+	models.MediaKindMain:      true,
+	models.MediaKindProcess:   true,
+	models.MediaKindDetail:    true,
+	models.MediaKindAlternate: true,
+}
+
+// --- Helper: load an artwork owned by the authenticated user ---
+func findOwnedArtwork(c *gin.Context, ctx context.Context) (*models.Artwork, bool) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork ID"})
+		return nil, false
+	}
+
+	var artwork models.Artwork
+	err = database.Collection("artworks").FindOne(ctx, activeArtworkFilter(bson.M{"_id": artworkID, "userId": userID})).Decode(&artwork)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found or you do not own it"})
+		return nil, false
+	}
+	return &artwork, true
+}
+
+func findMediaItem(artwork *models.Artwork, id primitive.ObjectID) (models.MediaItem, int) {
+	for i, item := range artwork.Media {
+		if item.ID == id {
+			return item, i
+		}
+	}
+	return models.MediaItem{}, -1
+}
+
+// AddArtworkMedia appends a file to an artwork, e.g. a WIP stage or a
+// detail crop. Set cover=true to make it the artwork's cover.
+func AddArtworkMedia(c *gin.Context) {
+	kind := c.DefaultPostForm("kind", models.MediaKindProcess)
+	if !mediaKinds[kind] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid media kind"})
+		return
+	}
+	caption := strings.TrimSpace(c.PostForm("caption"))
+	if len(caption) > 280 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "caption too long"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "file open failed"})
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "file read failed"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	artwork, ok := findOwnedArtwork(c, ctx)
+	if !ok {
+		return
+	}
+	if len(artwork.Media) >= models.MaxMediaPerArtwork {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("an artwork can hold at most %d media items", models.MaxMediaPerArtwork)})
+		return
+	}
+
+	// The route's middleware picked a size limit from the declared type;
+	// check again against what the bytes really are.
+	contentType := utils.SniffContentType(data)
+	if err := middleware.ValidateMedia(int64(len(data)), contentType, middleware.ArtworkMediaLimits); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stored, err := storeArtworkMedia(ctx, artwork.UserID, data, contentType)
+	if err != nil {
+		fmt.Println("Cloudinary upload error:", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, errVideoTooLong) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": "upload failed"})
+		return
+	}
+	item := stored.mediaItem(kind, caption)
+	if models.IsMatureRating(artwork.ContentRating) {
+		if err := storeBlurredPreview(ctx, &item); err != nil {
+			fmt.Println("Blurred preview error:", err)
+		}
+	}
+
+	set := bson.M{}
+	if c.PostForm("cover") == "true" {
+		set = coverFields(item)
+	}
+
+	update := bson.M{"$push": bson.M{"media": item}}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+
+	// The size guard keeps concurrent adds from exceeding the cap.
+	res, err := database.Collection("artworks").UpdateOne(ctx, bson.M{
+		"_id": artwork.ID,
+		fmt.Sprintf("media.%d", models.MaxMediaPerArtwork-1): bson.M{"$exists": false},
+	}, update)
+	if err != nil || res.MatchedCount == 0 {
+		destroyMediaItem(context.Background(), item)
+		c.JSON(http.StatusConflict, gin.H{"error": "failed to add media"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "media added",
+		"media":   item,
+	})
+}
+
+// RemoveArtworkMedia deletes one media item and its stored file. The last
+// item can't be removed; delete the artwork instead. Removing the cover
+// promotes the next item.
+func RemoveArtworkMedia(c *gin.Context) {
+	mediaID, err := primitive.ObjectIDFromHex(c.Param("mediaId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid media id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	artwork, ok := findOwnedArtwork(c, ctx)
+	if !ok {
+		return
+	}
+
+	item, idx := findMediaItem(artwork, mediaID)
+	if idx < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media not found"})
+		return
+	}
+	if len(artwork.Media) == 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "an artwork needs at least one media item"})
+		return
+	}
+
+	update := bson.M{"$pull": bson.M{"media": bson.M{"_id": mediaID}}}
+	if artwork.CoverMediaID == mediaID {
+		next := artwork.Media[0]
+		if idx == 0 {
+			next = artwork.Media[1]
+		}
+		update["$set"] = coverFields(next)
+	}
+
+	_, err = database.Collection("artworks").UpdateOne(ctx, bson.M{"_id": artwork.ID}, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove media"})
+		return
+	}
+
+	if err := destroyMediaItem(context.Background(), item); err != nil {
+		fmt.Println("Cloudinary deletion error:", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "media removed"})
+}
+
+// ReorderArtworkMedia sets the display order. mediaIds must list every item
+// exactly once; coverId optionally changes the cover at the same time.
+func ReorderArtworkMedia(c *gin.Context) {
+	var input struct {
+		MediaIDs []string `json:"mediaIds" binding:"required"`
+		CoverID  string   `json:"coverId"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	artwork, ok := findOwnedArtwork(c, ctx)
+	if !ok {
+		return
+	}
+
+	if len(input.MediaIDs) != len(artwork.Media) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mediaIds must list every media item exactly once"})
+		return
+	}
+
+	ordered := make([]models.MediaItem, 0, len(input.MediaIDs))
+	ids := make([]primitive.ObjectID, 0, len(input.MediaIDs))
+	seen := map[primitive.ObjectID]bool{}
+	for _, hex := range input.MediaIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil || seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mediaIds must list every media item exactly once"})
+			return
+		}
+		item, idx := findMediaItem(artwork, id)
+		if idx < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mediaIds must list every media item exactly once"})
+			return
+		}
+		seen[id] = true
+		ordered = append(ordered, item)
+		ids = append(ids, id)
+	}
+
+	set := bson.M{"media": ordered}
+	if input.CoverID != "" {
+		coverID, err := primitive.ObjectIDFromHex(input.CoverID)
+		if err != nil || !seen[coverID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cover id"})
+			return
+		}
+		cover, _ := findMediaItem(artwork, coverID)
+		for k, v := range coverFields(cover) {
+			set[k] = v
+		}
+	}
+
+	// Only apply if the item set hasn't changed since it was read.
+	res, err := database.Collection("artworks").UpdateOne(ctx, bson.M{
+		"_id":       artwork.ID,
+		"media":     bson.M{"$size": len(ids)},
+		"media._id": bson.M{"$all": ids},
+	}, bson.M{"$set": set})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reorder media"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "media changed, reload and try again"})
+		return
+	}
+
+	var updated models.Artwork
+	database.Collection("artworks").FindOne(
+		ctx,
+		bson.M{"_id": artwork.ID},
+		options.FindOne().SetProjection(bson.M{"media": 1, "coverMediaId": 1}),
+	).Decode(&updated)
+
+	c.JSON(http.StatusOK, gin.H{
+		"media":        updated.Media,
+		"coverMediaId": updated.CoverMediaID,
+	})
+}
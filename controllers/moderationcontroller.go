@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultDuplicateDistance = 6
+
+// NearDuplicate is an existing artwork whose perceptual hash is within the
+// duplicate threshold of a queried hash.
+type NearDuplicate struct {
+	ArtworkID primitive.ObjectID `json:"artworkId"`
+	UserID    primitive.ObjectID `json:"userId"`
+	Title     string             `json:"title"`
+	URL       string             `json:"url"`
+	IsPublic  bool               `json:"isPublic"`
+	Distance  int                `json:"distance"`
+}
+
+// duplicateDistance is read from DUPLICATE_HASH_DISTANCE and capped so the
+// band index still guarantees every match is found.
+func duplicateDistance() int {
+	d := defaultDuplicateDistance
+	if v, err := strconv.Atoi(os.Getenv("DUPLICATE_HASH_DISTANCE")); err == nil && v >= 0 {
+		d = v
+	}
+	if d > utils.HashBands-1 {
+		d = utils.HashBands - 1
+	}
+	return d
+}
+
+// --- Helper: find artworks within maxDistance bits of hash ---
+func findNearDuplicates(ctx context.Context, hash uint64, maxDistance int, filter bson.M) ([]NearDuplicate, error) {
+	query := activeArtworkFilter(bson.M{"hashBands": bson.M{"$in": utils.HashToBands(hash)}})
+	for k, v := range filter {
+		query[k] = v
+	}
+
+	opts := options.Find().SetProjection(bson.M{
+		"_id": 1, "userId": 1, "title": 1, "url": 1, "isPublic": 1, "perceptualHash": 1,
+	})
+
+	cursor, err := database.Collection("artworks").Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var candidates []models.Artwork
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+
+	matches := []NearDuplicate{}
+	for _, a := range candidates {
+		other, err := utils.ParseHash(a.PerceptualHash)
+		if err != nil {
+			continue
+		}
+		d := utils.HammingDistance(hash, other)
+		if d > maxDistance {
+			continue
+		}
+		matches = append(matches, NearDuplicate{
+			ArtworkID: a.ID,
+			UserID:    a.UserID,
+			Title:     a.Title,
+			URL:       a.URL,
+			IsPublic:  a.IsPublic,
+			Distance:  d,
+		})
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Distance < matches[j].Distance })
+	return matches, nil
+}
+
+// checkDuplicates compares a freshly stored artwork against the index. Matches
+// against the uploader's own work are returned as warnings; matches against
+// other artists' public work are flagged for moderators.
+func checkDuplicates(ctx context.Context, artwork models.Artwork, hash uint64) []NearDuplicate {
+	matches, err := findNearDuplicates(ctx, hash, duplicateDistance(), activeArtworkFilter(bson.M{
+		"_id": bson.M{"$ne": artwork.ID},
+	}))
+	if err != nil {
+		log.Println("Duplicate lookup failed:", err)
+		return nil
+	}
+
+	warnings := []NearDuplicate{}
+	var flags []interface{}
+	for _, m := range matches {
+		if m.UserID == artwork.UserID {
+			warnings = append(warnings, m)
+			continue
+		}
+		if !m.IsPublic {
+			continue
+		}
+		flags = append(flags, models.ModerationFlag{
+			ID:               primitive.NewObjectID(),
+			Type:             models.FlagTypeNearDuplicate,
+			Status:           models.FlagStatusOpen,
+			ArtworkID:        artwork.ID,
+			UserID:           artwork.UserID,
+			MatchedArtworkID: m.ArtworkID,
+			MatchedUserID:    m.UserID,
+			Distance:         m.Distance,
+			CreatedAt:        time.Now(),
+		})
+	}
+
+	if len(flags) > 0 {
+		if _, err := database.Collection("moderation_flags").InsertMany(ctx, flags); err != nil {
+			log.Println("Failed to record moderation flags:", err)
+		}
+	}
+
+	return warnings
+}
+
+func GetModerationFlags(c *gin.Context) {
+	status := c.DefaultQuery("status", models.FlagStatusOpen)
+
+	collection := database.Collection("moderation_flags")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.M{"createdAt": -1}).SetLimit(100)
+	cursor, err := collection.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch flags"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	flags := []models.ModerationFlag{}
+	if err := cursor.All(ctx, &flags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse flags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count": len(flags),
+		"flags": flags,
+	})
+}
+
+func ResolveModerationFlag(c *gin.Context) {
+	flagID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid flag id"})
+		return
+	}
+
+	reviewerID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Status string `json:"status" binding:"required,oneof=dismissed confirmed"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	collection := database.Collection("moderation_flags")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	res, err := collection.UpdateOne(ctx, bson.M{"_id": flagID}, bson.M{"$set": bson.M{
+		"status":     input.Status,
+		"reviewedBy": reviewerID,
+		"reviewedAt": now,
+	}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update flag"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "flag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "flag updated"})
+}
+
+// FindSimilarArtworks queries the Hamming-distance index, either by an
+// existing artwork id or by a raw hex hash.
+func FindSimilarArtworks(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var hash uint64
+	filter := bson.M{}
+
+	if idStr := c.Query("artworkId"); idStr != "" {
+		artworkID, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork id"})
+			return
+		}
+
+		var artwork models.Artwork
+		if err := database.Collection("artworks").FindOne(ctx, bson.M{"_id": artworkID}).Decode(&artwork); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found"})
+			return
+		}
+		if hash, err = utils.ParseHash(artwork.PerceptualHash); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "artwork has no perceptual hash"})
+			return
+		}
+		filter["_id"] = bson.M{"$ne": artworkID}
+	} else {
+		var err error
+		if hash, err = utils.ParseHash(c.Query("hash")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "artworkId or hex hash is required"})
+			return
+		}
+	}
+
+	maxDistance := duplicateDistance()
+	if v, err := strconv.Atoi(c.Query("maxDistance")); err == nil && v >= 0 && v < utils.HashBands {
+		maxDistance = v
+	}
+
+	matches, err := findNearDuplicates(ctx, hash, maxDistance, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "similarity lookup failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"hash":        utils.FormatHash(hash),
+		"maxDistance": maxDistance,
+		"count":       len(matches),
+		"matches":     matches,
+	})
+}
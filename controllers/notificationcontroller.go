@@ -0,0 +1,358 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const milestoneCheckInterval = 10 * time.Minute
+
+// notify stores a notification unless it would go to its own actor or the
+// recipient has muted its type. A repeat follow refreshes the earlier
+// notification rather than adding another. Failures are logged, not
+// returned: a missed notification shouldn't fail the action behind it.
+func notify(ctx context.Context, n models.Notification) {
+	if n.ActorID != nil && *n.ActorID == n.UserID {
+		return
+	}
+	muted, err := database.Collection("users").CountDocuments(
+		ctx,
+		bson.M{"_id": n.UserID, "muted_notifications": n.Type},
+		options.Count().SetLimit(1),
+	)
+	if err != nil || muted > 0 {
+		return
+	}
+
+	n.CreatedAt = time.Now()
+	notifications := database.Collection("notifications")
+	if n.Type == models.NotificationFollow {
+		_, err = notifications.UpdateOne(
+			ctx,
+			bson.M{"userId": n.UserID, "type": n.Type, "actorId": n.ActorID},
+			bson.M{"$set": bson.M{"read": false, "createdAt": n.CreatedAt}},
+			options.Update().SetUpsert(true),
+		)
+	} else {
+		_, err = notifications.InsertOne(ctx, n)
+	}
+	if err != nil {
+		fmt.Println("Failed to store notification:", err)
+	}
+}
+
+// CheckViewMilestones notifies artists when an artwork's views pass one of
+// models.ViewMilestones, checking every milestoneCheckInterval.
+func CheckViewMilestones() {
+	for {
+		time.Sleep(milestoneCheckInterval)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		if err := checkViewMilestones(ctx); err != nil {
+			fmt.Println("View milestone check failed:", err)
+		}
+		cancel()
+	}
+}
+
+// checkViewMilestones goes from the largest milestone down, so an artwork
+// that passed several since the last check is only notified of the highest.
+func checkViewMilestones(ctx context.Context) error {
+	artworks := database.Collection("artworks")
+	for i := len(models.ViewMilestones) - 1; i >= 0; i-- {
+		milestone := models.ViewMilestones[i]
+		cursor, err := artworks.Find(
+			ctx,
+			activeArtworkFilter(bson.M{
+				"views":         bson.M{"$gte": milestone},
+				"viewMilestone": bson.M{"$lt": milestone},
+			}),
+			options.Find().SetProjection(bson.M{"userId": 1, "title": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		var reached []models.Artwork
+		if err := cursor.All(ctx, &reached); err != nil {
+			return err
+		}
+
+		for _, a := range reached {
+			// The conditional update claims the milestone, so a notification
+			// is only sent once even if checks overlap.
+			res, err := artworks.UpdateOne(
+				ctx,
+				bson.M{"_id": a.ID, "viewMilestone": bson.M{"$lt": milestone}},
+				bson.M{"$set": bson.M{"viewMilestone": milestone}},
+			)
+			if err != nil || res.ModifiedCount == 0 {
+				continue
+			}
+			artworkID := a.ID
+			notify(ctx, models.Notification{
+				UserID:       a.UserID,
+				Type:         models.NotificationMilestone,
+				ArtworkID:    &artworkID,
+				ArtworkTitle: a.Title,
+				Milestone:    milestone,
+			})
+		}
+	}
+	return nil
+}
+
+// notificationView is a notification as served to clients, with whoever
+// caused it attached.
+type notificationView struct {
+	models.Notification
+	Actor *artistSummary `json:"actor,omitempty"`
+}
+
+// GetNotifications handles GET /notifications: the user's notifications,
+// newest first. ?unread=true leaves out the ones already read.
+func GetNotifications(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := parsePageRequest(c, sortNewest, sortNewest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter := bson.M{"userId": userID}
+	if c.Query("unread") == "true" {
+		filter["read"] = false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := database.Collection("notifications")
+	cursor, err := collection.Find(
+		ctx,
+		page.query(filter),
+		options.Find().SetSort(page.sortOrder()).SetLimit(int64(page.Limit+1)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch notifications"})
+		return
+	}
+	var notifications []models.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse notifications"})
+		return
+	}
+
+	next := ""
+	if len(notifications) > page.Limit {
+		notifications = notifications[:page.Limit]
+		last := notifications[len(notifications)-1]
+		next = encodeCursor(pageCursor{Sort: page.Sort, CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	var actorIDs []primitive.ObjectID
+	for _, n := range notifications {
+		if n.ActorID != nil {
+			actorIDs = append(actorIDs, *n.ActorID)
+		}
+	}
+	actors, err := findArtistSummaries(ctx, actorIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch notifications"})
+		return
+	}
+	byID := make(map[primitive.ObjectID]artistSummary, len(actors))
+	for _, a := range actors {
+		byID[a.ID] = a
+	}
+
+	views := make([]notificationView, len(notifications))
+	for i, n := range notifications {
+		views[i] = notificationView{Notification: n}
+		if n.ActorID != nil {
+			if a, ok := byID[*n.ActorID]; ok {
+				views[i].Actor = &a
+			}
+		}
+	}
+
+	unread, _ := collection.CountDocuments(ctx, bson.M{"userId": userID, "read": false})
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": views,
+		"count":         len(views),
+		"unreadCount":   unread,
+		"nextCursor":    next,
+		"hasMore":       next != "",
+	})
+}
+
+// UpdateNotification handles PATCH /notifications/:id, marking one
+// notification read or unread.
+func UpdateNotification(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	notificationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification id"})
+		return
+	}
+
+	var input struct {
+		Read *bool `json:"read" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := database.Collection("notifications").UpdateOne(
+		ctx,
+		bson.M{"_id": notificationID, "userId": userID},
+		bson.M{"$set": bson.M{"read": *input.Read}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": notificationID, "read": *input.Read})
+}
+
+// MarkAllNotificationsRead handles POST /notifications/read-all.
+func MarkAllNotificationsRead(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := database.Collection("notifications").UpdateMany(
+		ctx,
+		bson.M{"userId": userID, "read": false},
+		bson.M{"$set": bson.M{"read": true}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "notifications marked read", "updated": res.ModifiedCount})
+}
+
+func notificationPreferences(muted []string) gin.H {
+	prefs := gin.H{}
+	for _, t := range models.NotificationTypes {
+		prefs[t] = true
+	}
+	for _, t := range muted {
+		if models.IsValidNotificationType(t) {
+			prefs[t] = false
+		}
+	}
+	return prefs
+}
+
+// GetNotificationPreferences returns, for every notification type, whether
+// the user receives it.
+func GetNotificationPreferences(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"muted_notifications": 1}),
+	).Decode(&user)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationPreferences(user.MutedNotifications))
+}
+
+// UpdateNotificationPreferences turns notification types on or off. Types
+// left out of the body keep their current setting.
+func UpdateNotificationPreferences(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input map[string]bool
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	for t := range input {
+		if !models.IsValidNotificationType(t) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown notification type: " + t})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	users := database.Collection("users")
+	var user models.User
+	err := users.FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"muted_notifications": 1}),
+	).Decode(&user)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	muted := []string{}
+	for _, t := range models.NotificationTypes {
+		enabled, changed := input[t]
+		if !changed {
+			enabled = !slices.Contains(user.MutedNotifications, t)
+		}
+		if !enabled {
+			muted = append(muted, t)
+		}
+	}
+
+	_, err = users.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"muted_notifications": muted, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationPreferences(muted))
+}
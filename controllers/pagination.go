@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultPageSize = 24
+	maxPageSize     = 100
+)
+
+// Listing sort orders. Trending ranks by views among artworks created inside
+// trendingWindow; portfolio is the artist's manual order; published is
+// newest first by publication rather than upload time; oldest is for
+// reading threads in order.
+const (
+	sortNewest    = "newest"
+	sortOldest    = "oldest"
+	sortViews     = "views"
+	sortTrending  = "trending"
+	sortPortfolio = "portfolio"
+	sortPublished = "published"
+)
+
+const trendingWindow = 7 * 24 * time.Hour
+
+var (
+	errInvalidSort      = errors.New("invalid sort")
+	errInvalidCursor    = errors.New("invalid cursor")
+	errInvalidMediaType = errors.New("invalid media type")
+)
+
+// pageCursor marks the last artwork of a page. Clients get it as an opaque
+// token and pass it back as ?after=; it is only valid for the sort that
+// issued it.
+type pageCursor struct {
+	Sort        string             `json:"s"`
+	Views       int                `json:"v,omitempty"`
+	Position    *int               `json:"p,omitempty"`
+	PublishedAt *time.Time         `json:"pt,omitempty"`
+	CreatedAt   time.Time          `json:"t"`
+	ID          primitive.ObjectID `json:"i"`
+}
+
+type pageRequest struct {
+	Sort  string
+	Limit int
+	After *pageCursor
+}
+
+// parsePageRequest reads ?sort=, ?limit= and ?after=. The limit is clamped
+// rather than rejected; an unknown sort or a malformed cursor is an error.
+func parsePageRequest(c *gin.Context, defaultSort string, allowed ...string) (pageRequest, error) {
+	page := pageRequest{Sort: c.DefaultQuery("sort", defaultSort), Limit: defaultPageSize}
+
+	valid := false
+	for _, s := range allowed {
+		valid = valid || s == page.Sort
+	}
+	if !valid {
+		return page, errInvalidSort
+	}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		page.Limit = limit
+	}
+	if page.Limit > maxPageSize {
+		page.Limit = maxPageSize
+	}
+
+	if after := c.Query("after"); after != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(after)
+		if err != nil {
+			return page, errInvalidCursor
+		}
+		var cursor pageCursor
+		if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort != page.Sort || cursor.ID.IsZero() {
+			return page, errInvalidCursor
+		}
+		page.After = &cursor
+	}
+	return page, nil
+}
+
+func (p pageRequest) sortOrder() bson.D {
+	switch p.Sort {
+	case sortViews, sortTrending:
+		return bson.D{{Key: "views", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	case sortPortfolio:
+		return bson.D{{Key: "position", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	case sortPublished:
+		return bson.D{{Key: "publishedAt", Value: -1}, {Key: "_id", Value: -1}}
+	case sortOldest:
+		return bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}
+	}
+	return bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+}
+
+// keysetField is one column of a sort order with the cursor's value for it.
+type keysetField struct {
+	name  string
+	value interface{}
+	asc   bool
+}
+
+// keysetAfter matches the documents that sort strictly after the cursor.
+// Missing values sort first in ascending order, so a nil cursor value means
+// "any non-null value is after it".
+func keysetAfter(fields []keysetField) bson.M {
+	or := make([]bson.M, 0, len(fields))
+	for i, f := range fields {
+		clause := bson.M{}
+		for _, prev := range fields[:i] {
+			clause[prev.name] = prev.value
+		}
+		switch {
+		case f.asc && f.value == nil:
+			clause[f.name] = bson.M{"$ne": nil}
+		case f.asc:
+			clause[f.name] = bson.M{"$gt": f.value}
+		default:
+			clause[f.name] = bson.M{"$lt": f.value}
+		}
+		or = append(or, clause)
+	}
+	return bson.M{"$or": or}
+}
+
+// query returns a copy of filter narrowed to this page's sort and cursor.
+func (p pageRequest) query(filter bson.M) bson.M {
+	query := make(bson.M, len(filter)+1)
+	for k, v := range filter {
+		query[k] = v
+	}
+
+	var and []bson.M
+	if existing, ok := query["$and"].([]bson.M); ok {
+		and = append(and, existing...)
+	}
+	if p.Sort == sortTrending {
+		and = append(and, bson.M{"createdAt": bson.M{"$gte": time.Now().Add(-trendingWindow)}})
+	}
+
+	if a := p.After; a != nil {
+		tail := []keysetField{{name: "createdAt", value: a.CreatedAt}, {name: "_id", value: a.ID}}
+		switch p.Sort {
+		case sortViews, sortTrending:
+			tail = append([]keysetField{{name: "views", value: a.Views}}, tail...)
+		case sortPortfolio:
+			var position interface{}
+			if a.Position != nil {
+				position = *a.Position
+			}
+			tail = append([]keysetField{{name: "position", value: position, asc: true}}, tail...)
+		case sortPublished:
+			var publishedAt time.Time
+			if a.PublishedAt != nil {
+				publishedAt = *a.PublishedAt
+			}
+			tail = []keysetField{{name: "publishedAt", value: publishedAt}, {name: "_id", value: a.ID}}
+		case sortOldest:
+			tail = []keysetField{{name: "createdAt", value: a.CreatedAt, asc: true}, {name: "_id", value: a.ID, asc: true}}
+		}
+		and = append(and, keysetAfter(tail))
+	}
+
+	if len(and) > 0 {
+		query["$and"] = and
+	}
+	return query
+}
+
+func encodeCursor(cursor pageCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (p pageRequest) cursorFor(a models.Artwork) string {
+	return encodeCursor(pageCursor{
+		Sort:        p.Sort,
+		Views:       a.Views,
+		Position:    a.Position,
+		PublishedAt: a.PublishedAt,
+		CreatedAt:   a.CreatedAt,
+		ID:          a.ID,
+	})
+}
+
+// findPage loads one page of artworks matching filter. The returned cursor is
+// empty on the last page.
+func findPage(ctx context.Context, collection *mongo.Collection, filter bson.M, page pageRequest) ([]models.Artwork, string, error) {
+	opts := options.Find().
+		SetSort(page.sortOrder()).
+		SetLimit(int64(page.Limit + 1))
+
+	cursor, err := collection.Find(ctx, page.query(filter), opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	artworks := []models.Artwork{}
+	if err := cursor.All(ctx, &artworks); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(artworks) > page.Limit {
+		artworks = artworks[:page.Limit]
+		next = page.cursorFor(artworks[len(artworks)-1])
+	}
+	return artworks, next, nil
+}
+
+// findPinned loads the pinned artworks that match filter, in pin order.
+func findPinned(ctx context.Context, collection *mongo.Collection, filter bson.M, pinnedIDs []primitive.ObjectID) ([]models.Artwork, []primitive.ObjectID, error) {
+	if len(pinnedIDs) == 0 {
+		return []models.Artwork{}, []primitive.ObjectID{}, nil
+	}
+
+	query := make(bson.M, len(filter)+1)
+	for k, v := range filter {
+		query[k] = v
+	}
+	query["_id"] = bson.M{"$in": pinnedIDs}
+
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var artworks []models.Artwork
+	if err := cursor.All(ctx, &artworks); err != nil {
+		return nil, nil, err
+	}
+	artworks, pinned := pinFirst(artworks, pinnedIDs)
+	return artworks, pinned, nil
+}
+
+// findPublicArtworksByID loads the public artworks among ids, keeping the
+// order of ids. Ids that are no longer public are skipped.
+func findPublicArtworksByID(ctx context.Context, ids []primitive.ObjectID) ([]models.Artwork, error) {
+	artworks := []models.Artwork{}
+	if len(ids) == 0 {
+		return artworks, nil
+	}
+
+	cursor, err := database.Collection("artworks").Find(ctx, publicArtworkFilter(bson.M{"_id": bson.M{"$in": ids}}))
+	if err != nil {
+		return nil, err
+	}
+	var found []models.Artwork
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]models.Artwork, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			artworks = append(artworks, a)
+		}
+	}
+	return artworks, nil
+}
+
+// listingFilter applies the filters shared by every artwork listing: the
+// taxonomy filters plus ?mediaType=.
+func listingFilter(c *gin.Context, filter bson.M) (bson.M, error) {
+	filter, err := taxonomyFilter(c, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mediaType := c.Query("mediaType"); mediaType {
+	case "":
+	case models.MediaTypeImage, models.MediaTypeAnimated, models.MediaTypeVideo:
+		filter["mediaType"] = mediaType
+	default:
+		return nil, errInvalidMediaType
+	}
+	return filter, nil
+}
+
+// pageResponse is the envelope shared by the paged listings. Handlers add
+// their own keys, such as a total where counting is cheap.
+func pageResponse(artworks interface{}, count int, next string) gin.H {
+	return gin.H{
+		"artworks":   artworks,
+		"count":      count,
+		"nextCursor": next,
+		"hasMore":    next != "",
+	}
+}
@@ -0,0 +1,382 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	relatedRefreshInterval = 6 * time.Hour
+	relatedCoViewLookback  = 30 * 24 * time.Hour
+	maxRelatedArtworks     = 12
+	defaultRelatedLimit    = 8
+	minRelatedScore        = 0.05
+
+	// Viewers who opened more artworks than this are left out of co-view
+	// counts: crawlers and marathon browsing say little about similarity,
+	// and each history adds up to n²/2 pairs.
+	maxCoViewHistory = 50
+	// Tags on more artworks than this are too generic to find candidates
+	// with, though they still count toward tag overlap.
+	maxTagCandidates = 500
+	// Same-artist work clears minRelatedScore on the artist weight alone, so
+	// only the artist's most recent pieces are candidates; otherwise a
+	// prolific artist's catalogue is scored against itself in full.
+	maxArtistCandidates = 12
+)
+
+// Similarity weights, summing to 1.
+const (
+	relatedTagWeight     = 0.4
+	relatedCoViewWeight  = 0.3
+	relatedPaletteWeight = 0.15
+	relatedArtistWeight  = 0.15
+)
+
+// RefreshRelatedArtworks recomputes the related_artworks neighbour lists
+// straight away and then every relatedRefreshInterval. Artworks published in
+// between are served relatedFallback.
+func RefreshRelatedArtworks() {
+	for {
+		if err := refreshRelated(); err != nil {
+			fmt.Println("Related artworks refresh failed:", err)
+		}
+		time.Sleep(relatedRefreshInterval)
+	}
+}
+
+// coViews counts, over recent signed-in views, how many viewers opened each
+// artwork and each pair of artworks. Pairs are keyed smaller index first.
+func coViews(ctx context.Context, index map[primitive.ObjectID]int, since time.Time) ([]int, map[[2]int]int, error) {
+	cursor, err := database.Collection("view_events").Aggregate(ctx, []bson.M{
+		{"$match": bson.M{"userId": bson.M{"$ne": nil}, "createdAt": bson.M{"$gte": since}}},
+		{"$group": bson.M{"_id": "$userId", "artworks": bson.M{"$addToSet": "$artworkId"}}},
+		{"$match": bson.M{"$expr": bson.M{"$lte": bson.A{bson.M{"$size": "$artworks"}, maxCoViewHistory}}}},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	viewers := make([]int, len(index))
+	pairs := map[[2]int]int{}
+	for cursor.Next(ctx) {
+		var history struct {
+			Artworks []primitive.ObjectID `bson:"artworks"`
+		}
+		if err := cursor.Decode(&history); err != nil {
+			return nil, nil, err
+		}
+
+		var seen []int
+		for _, id := range history.Artworks {
+			if i, ok := index[id]; ok {
+				seen = append(seen, i)
+				viewers[i]++
+			}
+		}
+		sort.Ints(seen)
+		for a := 0; a < len(seen); a++ {
+			for b := a + 1; b < len(seen); b++ {
+				pairs[[2]int{seen[a], seen[b]}]++
+			}
+		}
+	}
+	return viewers, pairs, cursor.Err()
+}
+
+// BackfillPalettes computes the palette of image artworks uploaded before
+// palettes were recorded, sweeping hourly. Only a small derivative of each
+// image is fetched; artworks that fail are retried on the next sweep.
+func BackfillPalettes() {
+	for {
+		if err := backfillPalettes(); err != nil {
+			fmt.Println("Palette backfill failed:", err)
+		}
+		time.Sleep(time.Hour)
+	}
+}
+
+func backfillPalettes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	collection := database.Collection("artworks")
+	cursor, err := collection.Find(
+		ctx,
+		activeArtworkFilter(bson.M{
+			// Artworks from before videos were supported have no mediaType.
+			"mediaType": bson.M{"$in": bson.A{models.MediaTypeImage, models.MediaTypeAnimated, nil}},
+			"palette":   bson.M{"$exists": false},
+		}),
+		options.Find().SetProjection(bson.M{"publicId": 1, "originalPublicId": 1, "perceptualHash": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	filled := 0
+	for cursor.Next(ctx) {
+		var artwork models.Artwork
+		if err := cursor.Decode(&artwork); err != nil {
+			return err
+		}
+
+		// Watermarked artworks are printed from the clean original.
+		publicID, deliveryType := artwork.PublicID, api.Upload
+		if artwork.OriginalPublicID != "" {
+			publicID, deliveryType = artwork.OriginalPublicID, api.Authenticated
+		}
+		imagePrint, err := printStoredImage(ctx, publicID, deliveryType)
+		if err != nil {
+			fmt.Println("Palette backfill failed for", artwork.ID.Hex()+":", err)
+			continue
+		}
+
+		// An empty palette is stored too, so the artwork isn't fetched again.
+		set := bson.M{"palette": imagePrint.Palette}
+		if artwork.PerceptualHash == "" {
+			set["perceptualHash"] = utils.FormatHash(imagePrint.Hash)
+			set["hashBands"] = utils.HashToBands(imagePrint.Hash)
+		}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": artwork.ID, "palette": bson.M{"$exists": false}}, bson.M{"$set": set}); err != nil {
+			return err
+		}
+		filled++
+	}
+	if filled > 0 {
+		fmt.Println("Backfilled palettes for", filled, "artworks")
+	}
+	return cursor.Err()
+}
+
+func tagOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, t := range a {
+		set[t] = true
+	}
+	shared := 0
+	for _, t := range b {
+		if set[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// refreshRelated scores every public artwork against the candidates that
+// share a tag, an artist or a viewer with it, and keeps the best few. The
+// whole public catalogue is held in memory while it runs.
+func refreshRelated() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	cursor, err := database.Collection("artworks").Find(
+		ctx,
+		publicArtworkFilter(nil),
+		options.Find().
+			SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+			SetProjection(bson.M{"userId": 1, "tags": 1, "palette": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var artworks []models.Artwork
+	if err := cursor.All(ctx, &artworks); err != nil {
+		return err
+	}
+
+	index := make(map[primitive.ObjectID]int, len(artworks))
+	byTag := map[string][]int{}
+	byArtist := map[primitive.ObjectID][]int{}
+	for i, a := range artworks {
+		index[a.ID] = i
+		for _, t := range a.Tags {
+			byTag[t] = append(byTag[t], i)
+		}
+		// Artworks arrive newest first, so each list keeps the most recent.
+		if len(byArtist[a.UserID]) < maxArtistCandidates {
+			byArtist[a.UserID] = append(byArtist[a.UserID], i)
+		}
+	}
+
+	viewers, pairs, err := coViews(ctx, index, now.Add(-relatedCoViewLookback))
+	if err != nil {
+		return err
+	}
+	coViewed := map[int][]int{}
+	for pair := range pairs {
+		coViewed[pair[0]] = append(coViewed[pair[0]], pair[1])
+		coViewed[pair[1]] = append(coViewed[pair[1]], pair[0])
+	}
+
+	collection := database.Collection("related_artworks")
+	var writes []mongo.WriteModel
+	flush := func() error {
+		if len(writes) == 0 {
+			return nil
+		}
+		_, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		writes = writes[:0]
+		return err
+	}
+
+	for i, a := range artworks {
+		candidates := map[int]bool{}
+		for _, t := range a.Tags {
+			if len(byTag[t]) <= maxTagCandidates {
+				for _, j := range byTag[t] {
+					candidates[j] = true
+				}
+			}
+		}
+		for _, j := range byArtist[a.UserID] {
+			candidates[j] = true
+		}
+		for _, j := range coViewed[i] {
+			candidates[j] = true
+		}
+		delete(candidates, i)
+
+		neighbors := make([]models.RelatedNeighbor, 0, len(candidates))
+		for j := range candidates {
+			b := artworks[j]
+			score := relatedTagWeight*tagOverlap(a.Tags, b.Tags) +
+				relatedPaletteWeight*utils.PaletteSimilarity(a.Palette, b.Palette)
+			if a.UserID == b.UserID {
+				score += relatedArtistWeight
+			}
+			if n := pairs[[2]int{min(i, j), max(i, j)}]; n > 0 {
+				score += relatedCoViewWeight * float64(n) / math.Sqrt(float64(viewers[i]*viewers[j]))
+			}
+			if score >= minRelatedScore {
+				neighbors = append(neighbors, models.RelatedNeighbor{ArtworkID: b.ID, Score: score})
+			}
+		}
+		sort.Slice(neighbors, func(x, y int) bool { return neighbors[x].Score > neighbors[y].Score })
+		if len(neighbors) > maxRelatedArtworks {
+			neighbors = neighbors[:maxRelatedArtworks]
+		}
+
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": a.ID}).
+			SetReplacement(models.RelatedArtworks{ArtworkID: a.ID, Neighbors: neighbors, ComputedAt: now}).
+			SetUpsert(true))
+		if len(writes) == 500 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	_, err = collection.DeleteMany(ctx, bson.M{"computedAt": bson.M{"$lt": now}})
+	return err
+}
+
+// relatedFallback finds stand-ins for an artwork that has no neighbour list
+// yet: the most viewed public work by the same artist or sharing a tag.
+func relatedFallback(ctx context.Context, artwork models.Artwork) ([]primitive.ObjectID, error) {
+	or := []bson.M{{"userId": artwork.UserID}}
+	if len(artwork.Tags) > 0 {
+		or = append(or, bson.M{"tags": bson.M{"$in": artwork.Tags}})
+	}
+
+	cursor, err := database.Collection("artworks").Find(
+		ctx,
+		publicArtworkFilter(bson.M{"_id": bson.M{"$ne": artwork.ID}, "$or": or}),
+		options.Find().
+			SetSort(bson.M{"views": -1}).
+			SetLimit(maxRelatedArtworks).
+			SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var found []models.Artwork
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+	return artworkIDs(found), nil
+}
+
+// GetRelatedArtworks handles GET /artworks/:id/related.
+func GetRelatedArtworks(c *gin.Context) {
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var artwork models.Artwork
+	err = database.Collection("artworks").FindOne(
+		ctx,
+		linkableArtworkFilter(bson.M{"_id": artworkID}),
+	).Decode(&artwork)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found"})
+		return
+	}
+
+	var related models.RelatedArtworks
+	var ids []primitive.ObjectID
+	err = database.Collection("related_artworks").FindOne(ctx, bson.M{"_id": artworkID}).Decode(&related)
+	if err == nil && len(related.Neighbors) > 0 {
+		for _, n := range related.Neighbors {
+			ids = append(ids, n.ArtworkID)
+		}
+	} else if ids, err = relatedFallback(ctx, artwork); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch related artworks"})
+		return
+	}
+
+	artworks, err := findPublicArtworksByID(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch related artworks"})
+		return
+	}
+
+	preference := viewerMaturePreference(c, ctx)
+	shown := artworks[:0]
+	for _, a := range artworks {
+		if preference != models.MatureHide || !models.IsMatureRating(a.ContentRating) {
+			shown = append(shown, a)
+		}
+	}
+	if limit := boundedQueryInt(c, "limit", defaultRelatedLimit, maxRelatedArtworks); len(shown) > limit {
+		shown = shown[:limit]
+	}
+	fillAltText(shown)
+	gateArtworks(shown, preference)
+	markLikedByMe(c, ctx, shown)
+
+	c.JSON(http.StatusOK, gin.H{
+		"artworkId": artworkID,
+		"count":     len(shown),
+		"artworks":  shown,
+	})
+}
@@ -0,0 +1,442 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/middleware"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Resumable uploads follow the tus 1.0 core protocol (creation, HEAD, PATCH,
+// termination) with an extra commit step that turns the finished upload into
+// an artwork.
+const (
+	tusVersion          = "1.0.0"
+	uploadSessionTTL    = 24 * time.Hour
+	uploadChunkMaxBytes = 16 * 1024 * 1024
+)
+
+// sessionLocks is a fixed set of stripes rather than a lock per session, so
+// nothing has to be cleaned up when a session ends. Sessions sharing a
+// stripe only wait on each other briefly.
+var sessionLocks [64]sync.Mutex
+
+// lockSession serializes writes to one session within this process.
+func lockSession(id primitive.ObjectID) func() {
+	// The last byte of an ObjectID comes from its counter, so consecutive
+	// sessions land on different stripes.
+	l := &sessionLocks[int(id[len(id)-1])%len(sessionLocks)]
+	l.Lock()
+	return func() {
+		l.Unlock()
+	}
+}
+
+func uploadStagingDir() string {
+	if dir := os.Getenv("UPLOAD_TMP_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "artfolio-uploads")
+}
+
+func sessionFilePath(id primitive.ObjectID) string {
+	return filepath.Join(uploadStagingDir(), id.Hex())
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header:
+// comma-separated "key base64value" pairs.
+func parseUploadMetadata(header string) map[string]string {
+	meta := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), " ", 2)
+		if parts[0] == "" {
+			continue
+		}
+		if len(parts) == 1 {
+			meta[parts[0]] = ""
+			continue
+		}
+		if v, err := base64.StdEncoding.DecodeString(parts[1]); err == nil {
+			meta[parts[0]] = string(v)
+		}
+	}
+	return meta
+}
+
+// --- Helper: load a session owned by the current user ---
+func findUploadSession(c *gin.Context, ctx context.Context) (*models.UploadSession, bool) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload id"})
+		return nil, false
+	}
+
+	var session models.UploadSession
+	err = database.Collection("upload_sessions").FindOne(ctx, bson.M{
+		"_id":    sessionID,
+		"userId": userID,
+	}).Decode(&session)
+	if err != nil || session.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return nil, false
+	}
+	return &session, true
+}
+
+// CreateUploadSession starts a resumable upload. Clients send the total size
+// in Upload-Length and title/altText/filename/filetype in Upload-Metadata.
+func CreateUploadSession(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid Upload-Length header is required"})
+		return
+	}
+
+	meta := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	title := strings.TrimSpace(meta["title"])
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
+		return
+	}
+	altText := strings.TrimSpace(meta["altText"])
+	if _, err := checkAltText(altText); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	visibility, publishAt, err := requestedVisibility(meta["visibility"], meta["publishAt"])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := meta["filetype"]
+	if err := middleware.ValidateFile(length, contentType, middleware.ResumableMaxUploadMB, middleware.ArtworkAllowedTypes); err != nil {
+		status := http.StatusBadRequest
+		if err == middleware.ErrFileTooLarge {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := os.MkdirAll(uploadStagingDir(), 0o700); err != nil {
+		fmt.Println("Upload staging dir error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload"})
+		return
+	}
+
+	now := time.Now()
+	session := models.UploadSession{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Title:       title,
+		AltText:     altText,
+		Filename:    meta["filename"],
+		ContentType: contentType,
+		Length:      length,
+		Offset:      0,
+		Status:      models.UploadSessionActive,
+		ExpiresAt:   now.Add(uploadSessionTTL),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Visibility:  visibility,
+		PublishAt:   publishAt,
+	}
+
+	f, err := os.OpenFile(sessionFilePath(session.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		fmt.Println("Upload staging file error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload"})
+		return
+	}
+	f.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := database.Collection("upload_sessions").InsertOne(ctx, session); err != nil {
+		os.Remove(sessionFilePath(session.ID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload"})
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Location", "/artworks/uploads/"+session.ID.Hex())
+	c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetUploadOffset answers tus HEAD requests so clients know where to resume.
+func GetUploadOffset(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, ok := findUploadSession(c, ctx)
+	if !ok {
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.Length, 10))
+	c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusOK)
+}
+
+// PatchUpload appends a chunk at Upload-Offset, which must equal the number
+// of bytes already received.
+func PatchUpload(c *gin.Context) {
+	if c.ContentType() != "application/offset+octet-stream" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "content type must be application/offset+octet-stream"})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid Upload-Offset header is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	session, ok := findUploadSession(c, ctx)
+	if !ok {
+		return
+	}
+
+	unlock := lockSession(session.ID)
+	defer unlock()
+
+	// Re-read under the lock; another chunk may have landed meanwhile.
+	if err := database.Collection("upload_sessions").FindOne(ctx, bson.M{"_id": session.ID}).Decode(session); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return
+	}
+
+	if session.Status != models.UploadSessionActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "upload already committed"})
+		return
+	}
+	if offset != session.Offset {
+		c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+		c.JSON(http.StatusConflict, gin.H{"error": "offset mismatch", "offset": session.Offset})
+		return
+	}
+
+	remaining := session.Length - session.Offset
+	if remaining > uploadChunkMaxBytes {
+		remaining = uploadChunkMaxBytes
+	}
+
+	f, err := os.OpenFile(sessionFilePath(session.ID), os.O_WRONLY, 0o600)
+	if err != nil {
+		fmt.Println("Upload staging file error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to write chunk"})
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to write chunk"})
+		return
+	}
+
+	// Keep whatever arrived even if the connection drops mid-chunk; that is
+	// the whole point of resuming.
+	written, copyErr := io.Copy(f, io.LimitReader(c.Request.Body, remaining))
+	if written == 0 && copyErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read chunk"})
+		return
+	}
+	if err := f.Sync(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to write chunk"})
+		return
+	}
+
+	now := time.Now()
+	newOffset := offset + written
+	_, err = database.Collection("upload_sessions").UpdateOne(
+		ctx,
+		bson.M{"_id": session.ID, "offset": offset},
+		bson.M{"$set": bson.M{
+			"offset":    newOffset,
+			"updatedAt": now,
+			"expiresAt": now.Add(uploadSessionTTL),
+		}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record chunk"})
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Upload-Offset", strconv.FormatInt(newOffset, 10))
+	c.Header("Upload-Expires", now.Add(uploadSessionTTL).UTC().Format(http.TimeFormat))
+	c.Status(http.StatusNoContent)
+}
+
+// CommitUpload validates a fully received upload exactly like a regular
+// upload and creates the artwork.
+func CommitUpload(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	session, ok := findUploadSession(c, ctx)
+	if !ok {
+		return
+	}
+
+	unlock := lockSession(session.ID)
+	defer unlock()
+
+	// Re-read under the lock; a concurrent commit may have finished while
+	// this one waited.
+	if err := database.Collection("upload_sessions").FindOne(ctx, bson.M{"_id": session.ID}).Decode(session); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return
+	}
+
+	if session.Status == models.UploadSessionCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "upload already committed", "artworkId": session.ArtworkID})
+		return
+	}
+	if session.Offset != session.Length {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "upload incomplete",
+			"offset": session.Offset,
+			"length": session.Length,
+		})
+		return
+	}
+
+	data, err := os.ReadFile(sessionFilePath(session.ID))
+	if err != nil || int64(len(data)) != session.Length {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload data missing"})
+		return
+	}
+
+	// The declared filetype came from the client; trust the bytes instead.
+	contentType := utils.SniffContentType(data)
+	if err := middleware.ValidateFile(int64(len(data)), contentType, middleware.ResumableMaxUploadMB, middleware.ArtworkAllowedTypes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":        err.Error(),
+			"fileType":     contentType,
+			"allowedTypes": middleware.ArtworkAllowedTypes,
+		})
+		return
+	}
+
+	details := newArtworkDetails(session.Title)
+	details.AltText = session.AltText
+	if session.Visibility != "" {
+		details.Visibility = session.Visibility
+		details.PublishAt = session.PublishAt
+	}
+	details.MediaLimits = []middleware.TypeLimit{{Prefix: "image/", MaxSizeMB: middleware.ResumableMaxUploadMB}}
+
+	artwork, warnings, err := createArtwork(ctx, session.UserID, details, data)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	_, err = database.Collection("upload_sessions").UpdateOne(ctx, bson.M{"_id": session.ID}, bson.M{"$set": bson.M{
+		"status":    models.UploadSessionCompleted,
+		"artworkId": artwork.ID,
+		"updatedAt": time.Now(),
+	}})
+	if err != nil {
+		fmt.Println("Failed to mark upload session completed:", err)
+	}
+	os.Remove(sessionFilePath(session.ID))
+
+	response := gin.H{
+		"message": "upload successful",
+		"artwork": artwork,
+	}
+	if len(warnings) > 0 {
+		response["duplicateWarnings"] = warnings
+	}
+	if artwork.AltText == "" {
+		response["altTextWarning"] = missingAltTextWarning
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// CancelUpload implements tus termination.
+func CancelUpload(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, ok := findUploadSession(c, ctx)
+	if !ok {
+		return
+	}
+
+	unlock := lockSession(session.ID)
+	defer unlock()
+
+	if _, err := database.Collection("upload_sessions").DeleteOne(ctx, bson.M{"_id": session.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel upload"})
+		return
+	}
+	os.Remove(sessionFilePath(session.ID))
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Status(http.StatusNoContent)
+}
+
+// CleanupUploadSessions removes expired sessions and their staged bytes
+// every ten minutes.
+func CleanupUploadSessions() {
+	for {
+		time.Sleep(10 * time.Minute)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		collection := database.Collection("upload_sessions")
+		cursor, err := collection.Find(ctx, bson.M{"expiresAt": bson.M{"$lt": time.Now()}})
+		if err != nil {
+			fmt.Println("Upload session cleanup failed:", err)
+			cancel()
+			continue
+		}
+
+		var expired []models.UploadSession
+		cursor.All(ctx, &expired)
+
+		for _, s := range expired {
+			os.Remove(sessionFilePath(s.ID))
+			collection.DeleteOne(ctx, bson.M{"_id": s.ID})
+		}
+		cancel()
+	}
+}
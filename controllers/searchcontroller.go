@@ -0,0 +1,359 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/search"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultSearchLimit  = 20
+	maxSearchLimit      = 50
+	maxSearchOffset     = 1000
+	defaultSuggestLimit = 8
+	maxSuggestLimit     = 20
+)
+
+var (
+	errSearchQueryRequired = errors.New("search query is required")
+	errInvalidSearchType   = errors.New("invalid search type")
+)
+
+var searchIndex search.Index
+
+// InitSearch picks the search backend from SEARCH_BACKEND. "memory" keeps an
+// in-process index for local development, fed by RefreshSearchIndex; the
+// default searches MongoDB's text indexes directly.
+func InitSearch() {
+	if os.Getenv("SEARCH_BACKEND") == "memory" {
+		searchIndex = search.NewMemoryIndex()
+		return
+	}
+	searchIndex = search.NewMongoIndex(publicArtworkFilter(nil))
+}
+
+// RefreshSearchIndex runs every minute. It stores the search words of
+// artworks and users that predate them, and reloads indexes that keep their
+// own copy of the documents; the MongoDB backend otherwise reads live data.
+func RefreshSearchIndex() {
+	loadable, ok := searchIndex.(search.Loadable)
+	for {
+		if err := backfillSearchWords(); err != nil {
+			fmt.Println("search words backfill failed:", err)
+		}
+		if ok {
+			docs, err := loadSearchDocuments()
+			if err != nil {
+				fmt.Println("search index refresh failed:", err)
+			} else {
+				loadable.Replace(docs)
+			}
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
+// backfillSearchWords fills in the search words of artworks and users that
+// don't have them yet. A write is skipped if the title or name changed in
+// the meantime.
+func backfillSearchWords() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	targets := []struct{ collection, source, field string }{
+		{"artworks", "title", "searchWords"},
+		{"users", "full_name", "search_words"},
+	}
+	for _, target := range targets {
+		collection := database.Collection(target.collection)
+		cursor, err := collection.Find(
+			ctx,
+			bson.M{target.field: bson.M{"$exists": false}},
+			options.Find().SetProjection(bson.M{target.source: 1}),
+		)
+		if err != nil {
+			return err
+		}
+
+		var writes []mongo.WriteModel
+		flush := func() error {
+			if len(writes) == 0 {
+				return nil
+			}
+			_, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+			writes = writes[:0]
+			return err
+		}
+
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+			text, _ := doc[target.source].(string)
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": doc["_id"], target.source: doc[target.source], target.field: bson.M{"$exists": false}}).
+				SetUpdate(bson.M{"$set": bson.M{target.field: search.Words(text)}}))
+			if len(writes) == 500 {
+				if err := flush(); err != nil {
+					cursor.Close(ctx)
+					return err
+				}
+			}
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSearchDocuments reads every public artwork and every artist.
+func loadSearchDocuments() ([]search.Document, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cursor, err := database.Collection("users").Find(
+		ctx,
+		bson.M{},
+		options.Find().SetProjection(bson.M{"full_name": 1, "created_at": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	docs := make([]search.Document, 0, len(users))
+	names := make(map[primitive.ObjectID]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.FullName
+		docs = append(docs, search.Document{
+			ID:        u.ID,
+			Kind:      search.KindArtist,
+			Title:     u.FullName,
+			Handle:    utils.GenerateSlug(u.FullName),
+			CreatedAt: u.CreatedAt,
+		})
+	}
+
+	cursor, err = database.Collection("artworks").Find(ctx, publicArtworkFilter(nil))
+	if err != nil {
+		return nil, err
+	}
+	var artworks []models.Artwork
+	if err := cursor.All(ctx, &artworks); err != nil {
+		return nil, err
+	}
+	for _, a := range artworks {
+		docs = append(docs, search.Document{
+			ID:            a.ID,
+			Kind:          search.KindArtwork,
+			Title:         a.Title,
+			Description:   a.Description,
+			Tags:          a.Tags,
+			Medium:        a.Medium,
+			Category:      a.Category,
+			ArtistName:    names[a.UserID],
+			ContentRating: a.ContentRating,
+			CreatedAt:     a.CreatedAt,
+		})
+	}
+	return docs, nil
+}
+
+// boundedQueryInt reads a positive integer query parameter, falling back to
+// def when it is missing or invalid and clamping it to max.
+func boundedQueryInt(c *gin.Context, name string, def, max int) int {
+	n, err := strconv.Atoi(c.Query(name))
+	if err != nil || n < 1 {
+		return def
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// artistSummary is how an artist appears in lists: enough to link to their
+// portfolio.
+type artistSummary struct {
+	ID     primitive.ObjectID `json:"id"`
+	Name   string             `json:"name"`
+	Handle string             `json:"handle"`
+}
+
+// Search handles GET /search. ?type= narrows results to artworks or artists;
+// ?tag=, ?medium= and ?category= filter artworks and are reflected in the
+// facets.
+func Search(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	q := search.Query{
+		Text:     strings.TrimSpace(c.Query("q")),
+		Kind:     c.Query("type"),
+		Tags:     queryTags(c),
+		Medium:   c.Query("medium"),
+		Category: c.Query("category"),
+		Limit:    boundedQueryInt(c, "limit", defaultSearchLimit, maxSearchLimit),
+	}
+	if q.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errSearchQueryRequired.Error()})
+		return
+	}
+	if q.Kind != "" && q.Kind != search.KindArtwork && q.Kind != search.KindArtist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidSearchType.Error()})
+		return
+	}
+	if err := validateTaxonomy(q.Medium, q.Category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil && offset > 0 {
+		q.Offset = offset
+		if q.Offset > maxSearchOffset {
+			q.Offset = maxSearchOffset
+		}
+	}
+
+	preference := viewerMaturePreference(c, ctx)
+	q.HideMature = preference == models.MatureHide
+
+	results, err := searchIndex.Search(ctx, q)
+	if err != nil {
+		fmt.Println("search failed:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+		return
+	}
+
+	artworks, err := loadArtworkHits(ctx, results.Artworks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	fillAltText(artworks)
+	gateArtworks(artworks, preference)
+	markLikedByMe(c, ctx, artworks)
+
+	artists, err := loadArtistHits(ctx, results.Artists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artists"})
+		return
+	}
+
+	response := gin.H{
+		"query":         q.Text,
+		"artworks":      artworks,
+		"artists":       artists,
+		"totalArtworks": results.TotalArtworks,
+		"totalArtists":  results.TotalArtists,
+		"facets":        results.Facets,
+	}
+	if results.Corrected != "" {
+		response["correctedQuery"] = results.Corrected
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// loadArtworkHits fetches the hit artworks in rank order. Hits that stopped
+// being public since the index was built are dropped.
+func loadArtworkHits(ctx context.Context, hits []search.Hit) ([]models.Artwork, error) {
+	ids := make([]primitive.ObjectID, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return findPublicArtworksByID(ctx, ids)
+}
+
+func loadArtistHits(ctx context.Context, hits []search.Hit) ([]artistSummary, error) {
+	ids := make([]primitive.ObjectID, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return findArtistSummaries(ctx, ids)
+}
+
+// findArtistSummaries loads the artists among ids, keeping the order of ids.
+func findArtistSummaries(ctx context.Context, ids []primitive.ObjectID) ([]artistSummary, error) {
+	artists := []artistSummary{}
+	if len(ids) == 0 {
+		return artists, nil
+	}
+
+	cursor, err := database.Collection("users").Find(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"full_name": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	for _, id := range ids {
+		if u, ok := byID[id]; ok {
+			artists = append(artists, artistSummary{
+				ID:     u.ID,
+				Name:   u.FullName,
+				Handle: utils.GenerateSlug(u.FullName),
+			})
+		}
+	}
+	return artists, nil
+}
+
+// SearchSuggestions handles GET /search/suggest, completing ?q= as the
+// visitor types.
+func SearchSuggestions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	q := search.Query{
+		Text:  strings.TrimSpace(c.Query("q")),
+		Limit: boundedQueryInt(c, "limit", defaultSuggestLimit, maxSuggestLimit),
+	}
+	if q.Text == "" {
+		c.JSON(http.StatusOK, gin.H{"suggestions": []search.Suggestion{}})
+		return
+	}
+	q.HideMature = viewerMaturePreference(c, ctx) == models.MatureHide
+
+	suggestions, err := searchIndex.Suggest(ctx, q)
+	if err != nil {
+		fmt.Println("search suggestions failed:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch suggestions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
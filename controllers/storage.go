@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/nerokome/artfolio-backend/config"
+	"github.com/nerokome/artfolio-backend/middleware"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errCloudinaryNotInitialized = errors.New("cloudinary not initialized")
+
+var errVideoTooLong = fmt.Errorf("video is longer than %d seconds", middleware.VideoMaxSeconds)
+
+type storedMedia struct {
+	MediaType        string
+	URL              string
+	PublicID         string
+	Width            int
+	Height           int
+	Duration         float64
+	PosterURL        string
+	OriginalURL      string
+	OriginalPublicID string
+	Watermarked      bool
+}
+
+func (s *storedMedia) mediaItem(kind, caption string) models.MediaItem {
+	return models.MediaItem{
+		ID:               primitive.NewObjectID(),
+		Kind:             kind,
+		Caption:          caption,
+		MediaType:        s.MediaType,
+		URL:              s.URL,
+		PublicID:         s.PublicID,
+		Width:            s.Width,
+		Height:           s.Height,
+		Duration:         s.Duration,
+		PosterURL:        s.PosterURL,
+		Watermarked:      s.Watermarked,
+		OriginalURL:      s.OriginalURL,
+		OriginalPublicID: s.OriginalPublicID,
+		CreatedAt:        time.Now(),
+	}
+}
+
+// coverFields are the top-level artwork fields that mirror the cover item.
+func coverFields(item models.MediaItem) bson.M {
+	return bson.M{
+		"coverMediaId":     item.ID,
+		"mediaType":        item.MediaType,
+		"url":              item.URL,
+		"publicId":         item.PublicID,
+		"width":            item.Width,
+		"height":           item.Height,
+		"duration":         item.Duration,
+		"posterUrl":        item.PosterURL,
+		"watermarked":      item.Watermarked,
+		"originalUrl":      item.OriginalURL,
+		"originalPublicId": item.OriginalPublicID,
+		"blurredUrl":       item.BlurredURL,
+		"blurredPublicId":  item.BlurredPublicID,
+	}
+}
+
+func setCover(artwork *models.Artwork, item models.MediaItem) {
+	artwork.CoverMediaID = item.ID
+	artwork.MediaType = item.MediaType
+	artwork.URL = item.URL
+	artwork.PublicID = item.PublicID
+	artwork.Width = item.Width
+	artwork.Height = item.Height
+	artwork.Duration = item.Duration
+	artwork.PosterURL = item.PosterURL
+	artwork.Watermarked = item.Watermarked
+	artwork.OriginalURL = item.OriginalURL
+	artwork.OriginalPublicID = item.OriginalPublicID
+	artwork.BlurredURL = item.BlurredURL
+	artwork.BlurredPublicID = item.BlurredPublicID
+}
+
+// imageMedia describes an uploaded image; multi-page results are animated
+// GIFs or WebPs.
+func imageMedia(result *uploader.UploadResult) *storedMedia {
+	mediaType := models.MediaTypeImage
+	if result.Pages > 1 {
+		mediaType = models.MediaTypeAnimated
+	}
+	return &storedMedia{
+		MediaType: mediaType,
+		URL:       result.SecureURL,
+		PublicID:  result.PublicID,
+		Width:     result.Width,
+		Height:    result.Height,
+	}
+}
+
+// rawResultFloat reads a numeric field the SDK doesn't map, such as a
+// video's duration. The SDK keeps the decoded JSON body in Response as a
+// pointer to the object's map.
+func rawResultFloat(result *uploader.UploadResult, key string) float64 {
+	var fields map[string]interface{}
+	switch raw := result.Response.(type) {
+	case *map[string]interface{}:
+		if raw == nil {
+			return 0
+		}
+		fields = *raw
+	case map[string]interface{}:
+		fields = raw
+	default:
+		return 0
+	}
+	v, _ := fields[key].(float64)
+	return v
+}
+
+// videoPosterURL derives a JPEG of a video's first frame.
+func videoPosterURL(videoURL string) string {
+	poster := strings.Replace(videoURL, "/video/upload/", "/video/upload/so_0/", 1)
+	return strings.TrimSuffix(poster, path.Ext(poster)) + ".jpg"
+}
+
+// --- Helper: upload raw bytes to Cloudinary ---
+func uploadToCloudinary(ctx context.Context, data []byte, params uploader.UploadParams) (*uploader.UploadResult, error) {
+	if config.Cloudinary == nil {
+		return nil, errCloudinaryNotInitialized
+	}
+
+	result, err := config.Cloudinary.Upload.Upload(ctx, bytes.NewReader(data), params)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error.Message != "" {
+		return nil, errors.New(result.Error.Message)
+	}
+	return result, nil
+}
+
+// storeArtworkMedia uploads an artwork file. If the owner has watermarking
+// enabled the clean original of an image is stored as an authenticated
+// (private) asset and a watermarked copy becomes the public variant.
+func storeArtworkMedia(ctx context.Context, userID primitive.ObjectID, data []byte, contentType string) (*storedMedia, error) {
+	if utils.IsVideoContentType(contentType) {
+		return storeArtworkVideo(ctx, data)
+	}
+
+	settings, err := loadWatermarkSettings(ctx, userID)
+	if err != nil {
+		fmt.Println("Watermark settings lookup failed:", err)
+	}
+
+	transformation := utils.WatermarkTransformation(&settings)
+	if transformation == "" {
+		result, err := uploadToCloudinary(ctx, data, uploader.UploadParams{Folder: "artfolio"})
+		if err != nil {
+			return nil, err
+		}
+		return imageMedia(result), nil
+	}
+
+	original, err := uploadToCloudinary(ctx, data, uploader.UploadParams{
+		Folder: "artfolio/originals",
+		Type:   api.Authenticated,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := uploadToCloudinary(ctx, data, uploader.UploadParams{
+		Folder:         "artfolio",
+		Transformation: transformation,
+	})
+	if err != nil {
+		destroyAsset(ctx, original.PublicID, api.Authenticated)
+		return nil, err
+	}
+
+	stored := imageMedia(public)
+	stored.OriginalURL = original.SecureURL
+	stored.OriginalPublicID = original.PublicID
+	stored.Watermarked = true
+	return stored, nil
+}
+
+// storeArtworkVideo uploads a video and rejects it if it runs longer than
+// the allowed length. Videos are not watermarked.
+func storeArtworkVideo(ctx context.Context, data []byte) (*storedMedia, error) {
+	result, err := uploadToCloudinary(ctx, data, uploader.UploadParams{
+		Folder:       "artfolio",
+		ResourceType: string(api.Video),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	duration := rawResultFloat(result, "duration")
+	if duration > middleware.VideoMaxSeconds {
+		destroyMediaAsset(ctx, result.PublicID, api.Video, "")
+		return nil, errVideoTooLong
+	}
+
+	return &storedMedia{
+		MediaType: models.MediaTypeVideo,
+		URL:       result.SecureURL,
+		PublicID:  result.PublicID,
+		Width:     result.Width,
+		Height:    result.Height,
+		Duration:  duration,
+		PosterURL: videoPosterURL(result.SecureURL),
+	}, nil
+}
+
+// --- Helper: let Cloudinary fetch an asset from a URL itself ---
+func uploadURLToCloudinary(ctx context.Context, sourceURL string, params uploader.UploadParams) (*uploader.UploadResult, error) {
+	if config.Cloudinary == nil {
+		return nil, errCloudinaryNotInitialized
+	}
+
+	result, err := config.Cloudinary.Upload.Upload(ctx, sourceURL, params)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error.Message != "" {
+		return nil, errors.New(result.Error.Message)
+	}
+	return result, nil
+}
+
+// signedAssetURL builds a delivery URL that Cloudinary accepts for
+// authenticated assets. transformation may be empty.
+func signedAssetURL(publicID string, deliveryType api.DeliveryType, transformation string) (string, error) {
+	if config.Cloudinary == nil {
+		return "", errCloudinaryNotInitialized
+	}
+
+	img, err := config.Cloudinary.Image(publicID)
+	if err != nil {
+		return "", err
+	}
+	img.DeliveryType = deliveryType
+	img.Transformation = transformation
+	img.Config.URL.Secure = true
+	img.Config.URL.SignURL = true
+	return img.String()
+}
+
+func destroyAsset(ctx context.Context, publicID string, deliveryType string) error {
+	return destroyMediaAsset(ctx, publicID, api.Image, deliveryType)
+}
+
+func destroyMediaAsset(ctx context.Context, publicID string, resourceType api.AssetType, deliveryType string) error {
+	if config.Cloudinary == nil || publicID == "" {
+		return nil
+	}
+	_, err := config.Cloudinary.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     publicID,
+		Type:         deliveryType,
+		ResourceType: string(resourceType),
+	})
+	return err
+}
+
+func destroyMediaItem(ctx context.Context, item models.MediaItem) error {
+	// Blurred previews are images, video posters included.
+	if err := destroyAsset(ctx, item.BlurredPublicID, ""); err != nil {
+		return err
+	}
+	if item.MediaType == models.MediaTypeVideo {
+		return destroyMediaAsset(ctx, item.PublicID, api.Video, "")
+	}
+	if err := destroyAsset(ctx, item.PublicID, ""); err != nil {
+		return err
+	}
+	return destroyAsset(ctx, item.OriginalPublicID, api.Authenticated)
+}
+
+// storeBlurredPreview uploads a blurred copy of a media item's still (the
+// image, or a video's poster frame) as an asset of its own. The blur is
+// applied on upload and the copy gets a random public id, so nothing about
+// the preview leads back to the original.
+func storeBlurredPreview(ctx context.Context, item *models.MediaItem) error {
+	source := item.URL
+	if item.MediaType == models.MediaTypeVideo {
+		source = item.PosterURL
+	}
+	if source == "" {
+		return nil
+	}
+
+	result, err := uploadURLToCloudinary(ctx, source, uploader.UploadParams{
+		Folder:         "artfolio/blurred",
+		Transformation: utils.BlurTransformation,
+	})
+	if err != nil {
+		return err
+	}
+	item.BlurredURL = result.SecureURL
+	item.BlurredPublicID = result.PublicID
+	return nil
+}
+
+// destroyArtworkAssets removes every stored asset belonging to an artwork.
+// Artworks created before multi-media support only have the top-level asset.
+func destroyArtworkAssets(ctx context.Context, artwork models.Artwork) error {
+	items := artwork.Media
+	if len(items) == 0 {
+		items = []models.MediaItem{{
+			MediaType:        artwork.MediaType,
+			PublicID:         artwork.PublicID,
+			OriginalPublicID: artwork.OriginalPublicID,
+			BlurredPublicID:  artwork.BlurredPublicID,
+		}}
+	}
+
+	var errs []error
+	for _, item := range items {
+		if err := destroyMediaItem(ctx, item); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
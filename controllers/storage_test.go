@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+// uploadResult decodes body the way the SDK decodes an upload response.
+func uploadResult(t *testing.T, body string) *uploader.UploadResult {
+	t.Helper()
+	var result uploader.UploadResult
+	if err := api.HandleRawResponse([]byte(body), &result); err != nil {
+		t.Fatalf("HandleRawResponse: %v", err)
+	}
+	return &result
+}
+
+func TestRawResultFloat(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		key  string
+		want float64
+	}{
+		{"duration", `{"public_id":"artfolio/a","duration":12.5}`, "duration", 12.5},
+		{"integer", `{"public_id":"artfolio/a","duration":90}`, "duration", 90},
+		{"over the cap", `{"public_id":"artfolio/a","resource_type":"video","duration":600.04}`, "duration", 600.04},
+		{"missing", `{"public_id":"artfolio/a"}`, "duration", 0},
+		{"not a number", `{"public_id":"artfolio/a","duration":"long"}`, "duration", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rawResultFloat(uploadResult(t, tt.body), tt.key); got != tt.want {
+				t.Errorf("rawResultFloat = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := rawResultFloat(&uploader.UploadResult{}, "duration"); got != 0 {
+		t.Errorf("rawResultFloat without a response = %v, want 0", got)
+	}
+}
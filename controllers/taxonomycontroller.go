@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	errInvalidMedium   = errors.New("invalid medium")
+	errInvalidCategory = errors.New("invalid category")
+)
+
+// validateTaxonomy checks optional medium and category values against the
+// controlled vocabularies. Empty values are allowed.
+func validateTaxonomy(medium, category string) error {
+	if medium != "" && !models.IsValidMedium(medium) {
+		return errInvalidMedium
+	}
+	if category != "" && !models.IsValidCategory(category) {
+		return errInvalidCategory
+	}
+	return nil
+}
+
+// splitTags reads tags sent as one comma-separated form value.
+func splitTags(value string) []string {
+	return utils.NormalizeTags(strings.Split(value, ","))
+}
+
+// queryTags reads ?tag=, which may repeat or be comma-separated.
+func queryTags(c *gin.Context) []string {
+	var tags []string
+	for _, t := range c.QueryArray("tag") {
+		tags = append(tags, strings.Split(t, ",")...)
+	}
+	return utils.NormalizeTags(tags)
+}
+
+// taxonomyFilter narrows filter by the ?tag=, ?medium= and ?category= query
+// parameters. Artworks must have all of the tags.
+func taxonomyFilter(c *gin.Context, filter bson.M) (bson.M, error) {
+	if tags := queryTags(c); len(tags) > 0 {
+		filter["tags"] = bson.M{"$all": tags}
+	}
+
+	medium, category := c.Query("medium"), c.Query("category")
+	if err := validateTaxonomy(medium, category); err != nil {
+		return nil, err
+	}
+	if medium != "" {
+		filter["medium"] = medium
+	}
+	if category != "" {
+		filter["category"] = category
+	}
+	return filter, nil
+}
+
+type taxonomyTerm struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+func sortedTerms(vocabulary map[string]string) []taxonomyTerm {
+	terms := make([]taxonomyTerm, 0, len(vocabulary))
+	for value, label := range vocabulary {
+		terms = append(terms, taxonomyTerm{Value: value, Label: label})
+	}
+	sort.Slice(terms, func(i, j int) bool { return terms[i].Label < terms[j].Label })
+	return terms
+}
+
+// GetTaxonomy lists the allowed mediums, categories, licenses and content
+// flags.
+func GetTaxonomy(c *gin.Context) {
+	licenses := make([]taxonomyTerm, 0, len(models.LicenseTypes))
+	for value, info := range models.LicenseTypes {
+		licenses = append(licenses, taxonomyTerm{Value: value, Label: info.Name})
+	}
+	sort.Slice(licenses, func(i, j int) bool { return licenses[i].Value < licenses[j].Value })
+
+	c.JSON(http.StatusOK, gin.H{
+		"mediums":    sortedTerms(models.Mediums),
+		"categories": sortedTerms(models.Categories),
+		"licenses":   licenses,
+		"contentRatings": []string{
+			models.RatingGeneral,
+			models.RatingMature,
+			models.RatingExplicit,
+		},
+		"contentWarnings": sortedTerms(models.ContentWarnings),
+	})
+}
+
+// GetTags returns tags used on public artworks with their usage counts,
+// most used first. ?q= narrows to tags starting with a prefix for
+// autocomplete.
+func GetTags(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	match := publicArtworkFilter(nil)
+	var prefix bson.M
+	if q := strings.Trim(utils.Slugify(strings.TrimSpace(c.Query("q"))), "-"); q != "" {
+		prefix = bson.M{"tags": bson.M{"$regex": "^" + regexp.QuoteMeta(q)}}
+		match["tags"] = prefix["tags"]
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$project": bson.M{"tags": 1}},
+		{"$unwind": "$tags"},
+	}
+	if prefix != nil {
+		pipeline = append(pipeline, bson.M{"$match": prefix})
+	}
+	pipeline = append(pipeline,
+		bson.M{"$group": bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": limit},
+		bson.M{"$project": bson.M{"_id": 0, "tag": "$_id", "count": 1}},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.Collection("artworks").Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tags"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	tags := []bson.M{}
+	if err := cursor.All(ctx, &tags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultTrashRetentionDays = 30
+
+// trashRetention is how long deleted artworks stay restorable. Set
+// TRASH_RETENTION_DAYS to override.
+func trashRetention() time.Duration {
+	days := defaultTrashRetentionDays
+	if v, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && v > 0 {
+		days = v
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// trashedArtwork is an artwork in the trash along with when it will be
+// purged.
+type trashedArtwork struct {
+	models.Artwork
+	PurgeAt time.Time `json:"purgeAt"`
+}
+
+// GetTrash lists the authenticated user's deleted artworks, most recently
+// deleted first.
+func GetTrash(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.Collection("artworks").Find(
+		ctx,
+		bson.M{"userId": userID, "deletedAt": bson.M{"$ne": nil}},
+		options.Find().SetSort(bson.M{"deletedAt": -1}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trash"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var artworks []models.Artwork
+	if err := cursor.All(ctx, &artworks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse artworks"})
+		return
+	}
+
+	retention := trashRetention()
+	trashed := make([]trashedArtwork, len(artworks))
+	for i, a := range artworks {
+		trashed[i] = trashedArtwork{Artwork: a, PurgeAt: a.DeletedAt.Add(retention)}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":         len(trashed),
+		"retentionDays": int(retention.Hours() / 24),
+		"artworks":      trashed,
+	})
+}
+
+// RestoreArtwork takes an artwork out of the trash with its previous
+// visibility, views and media intact.
+func RestoreArtwork(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var artwork models.Artwork
+	err = database.Collection("artworks").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": artworkID, "userId": userID, "deletedAt": bson.M{"$ne": nil}},
+		bson.M{"$unset": bson.M{"deletedAt": ""}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&artwork)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "artwork restored",
+		"artwork": artwork,
+	})
+}
+
+// PurgeArtwork permanently deletes an artwork that is already in the trash,
+// without waiting for the retention period.
+func PurgeArtwork(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	artworkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artwork ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var artwork models.Artwork
+	err = database.Collection("artworks").FindOne(
+		ctx,
+		bson.M{"_id": artworkID, "userId": userID, "deletedAt": bson.M{"$ne": nil}},
+	).Decode(&artwork)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found in trash"})
+		return
+	}
+
+	if err := purgeArtwork(ctx, artwork); err != nil {
+		fmt.Println("Artwork purge failed:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete artwork from cloud storage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "artwork permanently deleted"})
+}
+
+// purgeArtwork destroys an artwork's stored files and then its record. The
+// record is kept if storage cleanup fails so the purge can be retried.
+func purgeArtwork(ctx context.Context, artwork models.Artwork) error {
+	if err := destroyArtworkAssets(ctx, artwork); err != nil {
+		return err
+	}
+	if _, err := database.Collection("artworks").DeleteOne(ctx, bson.M{"_id": artwork.ID}); err != nil {
+		return err
+	}
+	if _, err := database.Collection("collections").UpdateMany(
+		ctx,
+		bson.M{"artworkIds": artwork.ID},
+		bson.M{"$pull": bson.M{"artworkIds": artwork.ID}},
+	); err != nil {
+		return err
+	}
+	if _, err := database.Collection("likes").DeleteMany(ctx, bson.M{"artworkId": artwork.ID}); err != nil {
+		return err
+	}
+	if _, err := database.Collection("comments").DeleteMany(ctx, bson.M{"artworkId": artwork.ID}); err != nil {
+		return err
+	}
+	if _, err := database.Collection("notifications").DeleteMany(ctx, bson.M{"artworkId": artwork.ID}); err != nil {
+		return err
+	}
+	_, err := database.Collection("users").UpdateOne(
+		ctx,
+		bson.M{"_id": artwork.UserID},
+		bson.M{"$pull": bson.M{"pinned_artworks": artwork.ID}},
+	)
+	return err
+}
+
+// PurgeDeletedArtworks permanently removes artworks that have been in the
+// trash longer than the retention period, up to 100 an hour.
+func PurgeDeletedArtworks() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		cursor, err := database.Collection("artworks").Find(
+			ctx,
+			bson.M{"deletedAt": bson.M{"$lt": time.Now().Add(-trashRetention())}},
+			options.Find().SetLimit(100),
+		)
+		if err != nil {
+			fmt.Println("Trash purge failed:", err)
+		} else {
+			var expired []models.Artwork
+			cursor.All(ctx, &expired)
+
+			for _, artwork := range expired {
+				if err := purgeArtwork(ctx, artwork); err != nil {
+					fmt.Println("Failed to purge artwork", artwork.ID.Hex()+":", err)
+				}
+			}
+		}
+		cancel()
+
+		time.Sleep(time.Hour)
+	}
+}
@@ -2,22 +2,32 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/gin-gonic/gin"
-	"github.com/nerokome/artfolio-backend/config"
 	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/middleware"
 	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/search"
+	"github.com/nerokome/artfolio-backend/utils"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ownerArtwork is the owner's view of an artwork, which includes the clean
+// original of a watermarked piece.
+type ownerArtwork struct {
+	models.Artwork
+	OriginalURL string `json:"originalUrl,omitempty"`
+}
+
 func UploadArtwork(c *gin.Context) {
 	title := c.PostForm("title")
 	if title == "" {
@@ -38,23 +48,9 @@ func UploadArtwork(c *gin.Context) {
 	}
 	defer src.Close()
 
-	if config.Cloudinary == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "cloudinary not initialized"})
-		return
-	}
-
-	params := uploader.UploadParams{
-		Folder: "artfolio",
-	}
-
-	result, err := config.Cloudinary.Upload.Upload(
-		context.Background(),
-		src,
-		params,
-	)
+	data, err := io.ReadAll(src)
 	if err != nil {
-		fmt.Println("Cloudinary upload error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload failed"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "file read failed"})
 		return
 	}
 
@@ -70,31 +66,249 @@ func UploadArtwork(c *gin.Context) {
 		return
 	}
 
-	artwork := models.Artwork{
-		ID:        primitive.NewObjectID(),
-		UserID:    userID,
-		Title:     title,
-		Slug:      title,
-		URL:       result.SecureURL,
-		PublicID:  result.PublicID,
-		Views:     0,
-		IsPublic:  true,
-		CreatedAt: time.Now(),
+	details := newArtworkDetails(title)
+	details.Loop = c.PostForm("loop") == "true"
+	details.Tags = splitTags(c.PostForm("tags"))
+	details.Medium = c.PostForm("medium")
+	details.Category = c.PostForm("category")
+	details.Description = strings.TrimSpace(c.PostForm("description"))
+	if err := validateDescription(details.Description); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if licenseType := c.PostForm("license"); licenseType != "" {
+		details.License = &models.License{
+			Type:        licenseType,
+			CustomText:  c.PostForm("licenseText"),
+			Attribution: c.PostForm("attribution"),
+		}
+		if err := normalizeLicense(details.License); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	details.ContentRating = c.PostForm("contentRating")
+	if err := validateContentRating(details.ContentRating); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if details.ContentWarnings, err = splitContentWarnings(c.PostForm("contentWarnings")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	details.AltText = strings.TrimSpace(c.PostForm("altText"))
+	altTextWarning, err := checkAltText(details.AltText)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateTaxonomy(details.Medium, details.Category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if v := c.PostForm("visibility"); v != "" {
+		details.Visibility = v
+	}
+	if details.PublishAt, err = parsePublishAt(c.PostForm("publishAt")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateVisibility(details.Visibility, details.PublishAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	collection := database.Collection("artworks")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	if _, err := collection.InsertOne(ctx, artwork); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "db save failed"})
+	artwork, warnings, err := createArtwork(ctx, userID, details, data)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"message": "upload successful",
 		"artwork": artwork,
-	})
+	}
+	if len(warnings) > 0 {
+		response["duplicateWarnings"] = warnings
+	}
+	if altTextWarning != "" {
+		response["altTextWarning"] = altTextWarning
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+var (
+	errUploadFailed = errors.New("upload failed")
+	errDBSaveFailed = errors.New("db save failed")
+)
+
+// artworkDetails are the user-supplied fields of a new artwork, independent
+// of how its file was uploaded.
+type artworkDetails struct {
+	Title    string
+	Tags     []string
+	Medium   string
+	Category string
+
+	// Description is Markdown; saveArtwork renders it.
+	Description string
+	AltText     string
+
+	// License is nil to use the artist's default.
+	License *models.License
+
+	ContentRating   string
+	ContentWarnings []string
+
+	Visibility string
+	PublishAt  *time.Time
+	Loop       bool
+
+	// ID is set by callers that record the artwork's id before creating
+	// it; otherwise a new one is generated.
+	ID primitive.ObjectID
+
+	// MediaLimits caps the file's size by its sniffed type. Paths with
+	// their own caps replace middleware.ArtworkMediaLimits.
+	MediaLimits []middleware.TypeLimit
+}
+
+func newArtworkDetails(title string) artworkDetails {
+	return artworkDetails{
+		Title:       title,
+		Visibility:  models.VisibilityPublic,
+		MediaLimits: middleware.ArtworkMediaLimits,
+	}
+}
+
+// uploadErrorStatus maps an error from createArtwork or storeArtworkMedia
+// to a response status: problems with the file itself are the client's.
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errVideoTooLong),
+		errors.Is(err, middleware.ErrFileEmpty),
+		errors.Is(err, middleware.ErrFileTooLarge),
+		errors.Is(err, middleware.ErrInvalidFileType):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// createArtwork stores an uploaded file and inserts its artwork record. It is
+// shared by every upload path so hashing, watermarking and duplicate checks
+// behave the same regardless of how the bytes arrived.
+func createArtwork(ctx context.Context, userID primitive.ObjectID, details artworkDetails, data []byte) (models.Artwork, []NearDuplicate, error) {
+	// The route's middleware picked a size limit from the declared type;
+	// check again against what the bytes really are.
+	contentType := utils.SniffContentType(data)
+	if err := middleware.ValidateMedia(int64(len(data)), contentType, details.MediaLimits); err != nil {
+		return models.Artwork{}, nil, err
+	}
+
+	// Videos and formats the decoder doesn't understand are stored without
+	// a hash or palette.
+	var imagePrint utils.ImagePrint
+	printErr := errors.New("not an image")
+	if !utils.IsVideoContentType(contentType) {
+		imagePrint, printErr = utils.ImagePrintBytes(data)
+		if printErr != nil {
+			fmt.Println("Perceptual hash skipped:", printErr)
+		}
+	}
+
+	stored, err := storeArtworkMedia(ctx, userID, data, contentType)
+	if err != nil {
+		fmt.Println("Cloudinary upload error:", err)
+		if errors.Is(err, errVideoTooLong) {
+			return models.Artwork{}, nil, err
+		}
+		return models.Artwork{}, nil, errUploadFailed
+	}
+
+	if printErr != nil {
+		return saveArtwork(ctx, userID, details, stored, nil)
+	}
+	return saveArtwork(ctx, userID, details, stored, &imagePrint)
+}
+
+// saveArtwork inserts the record for media that is already in storage.
+// imagePrint is nil when the image could not be decoded. The stored assets
+// are removed again if the insert fails.
+func saveArtwork(ctx context.Context, userID primitive.ObjectID, details artworkDetails, stored *storedMedia, imagePrint *utils.ImagePrint) (models.Artwork, []NearDuplicate, error) {
+	artwork := models.Artwork{
+		ID:          details.ID,
+		UserID:      userID,
+		Title:       details.Title,
+		Slug:        details.Title,
+		SearchWords: search.Words(details.Title),
+		Tags:        details.Tags,
+		Medium:      details.Medium,
+		Category:    details.Category,
+		Description: details.Description,
+		AltText:     details.AltText,
+
+		ContentRating:   details.ContentRating,
+		ContentWarnings: details.ContentWarnings,
+		Views:           0,
+		CreatedAt:       time.Now(),
+	}
+	if artwork.ID.IsZero() {
+		artwork.ID = primitive.NewObjectID()
+	}
+	applyVisibility(&artwork, details.Visibility, details.PublishAt)
+	if details.Description != "" {
+		rendered, err := utils.RenderMarkdown(details.Description)
+		if err != nil {
+			if err := destroyArtworkAssets(context.Background(), artwork); err != nil {
+				fmt.Println("Cloudinary cleanup error:", err)
+			}
+			return models.Artwork{}, nil, err
+		}
+		artwork.DescriptionHTML = rendered
+	}
+	cover := stored.mediaItem(models.MediaKindMain, "")
+	if models.IsMatureRating(artwork.ContentRating) {
+		// GenerateBlurredPreviews retries if this fails.
+		if err := storeBlurredPreview(ctx, &cover); err != nil {
+			fmt.Println("Blurred preview error:", err)
+		}
+	}
+	artwork.Media = []models.MediaItem{cover}
+	setCover(&artwork, cover)
+	if artwork.MediaType != models.MediaTypeImage {
+		artwork.Loop = details.Loop
+	}
+	if imagePrint != nil {
+		artwork.PerceptualHash = utils.FormatHash(imagePrint.Hash)
+		artwork.HashBands = utils.HashToBands(imagePrint.Hash)
+		artwork.Palette = imagePrint.Palette
+	}
+
+	license, err := resolveLicense(ctx, userID, details.License)
+	if err != nil {
+		if err := destroyArtworkAssets(context.Background(), artwork); err != nil {
+			fmt.Println("Cloudinary cleanup error:", err)
+		}
+		return models.Artwork{}, nil, errDBSaveFailed
+	}
+	artwork.License = license
+
+	if _, err := database.Collection("artworks").InsertOne(ctx, artwork); err != nil {
+		if err := destroyArtworkAssets(context.Background(), artwork); err != nil {
+			fmt.Println("Cloudinary cleanup error:", err)
+		}
+		return models.Artwork{}, nil, errDBSaveFailed
+	}
+
+	var warnings []NearDuplicate
+	if imagePrint != nil {
+		warnings = checkDuplicates(ctx, artwork, imagePrint.Hash)
+	}
+	return artwork, warnings, nil
 }
 
 func GetPublicArtworks(c *gin.Context) {
@@ -103,26 +317,38 @@ func GetPublicArtworks(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	opts := options.Find().SetSort(bson.M{"createdAt": -1})
-
-	cursor, err := collection.Find(
-		ctx,
-		bson.M{"isPublic": true},
-		opts,
-	)
+	page, err := parsePageRequest(c, sortNewest, sortNewest, sortViews, sortTrending)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := listingFilter(c, publicArtworkFilter(nil))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer cursor.Close(ctx)
+	preference := viewerMaturePreference(c, ctx)
+	filter = matureContentFilter(filter, preference)
 
-	var artworks []models.Artwork
-	if err := cursor.All(ctx, &artworks); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse artworks"})
+	// No total here: counting the whole public catalogue on every page is
+	// the cost pagination is meant to avoid.
+	artworks, next, err := findPage(ctx, collection, filter, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
 		return
 	}
+	fillAltText(artworks)
+	gateArtworks(artworks, preference)
+	markLikedByMe(c, ctx, artworks)
 
-	c.JSON(http.StatusOK, artworks)
+	c.JSON(http.StatusOK, pageResponse(artworks, len(artworks), next))
+}
+
+// artworkPage is an artwork as shown on its public page.
+type artworkPage struct {
+	models.Artwork
+	SEO    map[string]interface{} `json:"seo"`
+	JSONLD map[string]interface{} `json:"jsonLd"`
 }
 
 func GetArtworkAndCountView(c *gin.Context) {
@@ -141,10 +367,7 @@ func GetArtworkAndCountView(c *gin.Context) {
 	var artwork models.Artwork
 	err = collection.FindOne(
 		ctx,
-		bson.M{
-			"_id":      artworkID,
-			"isPublic": true,
-		},
+		linkableArtworkFilter(bson.M{"_id": artworkID}),
 	).Decode(&artwork)
 
 	if err != nil {
@@ -158,7 +381,41 @@ func GetArtworkAndCountView(c *gin.Context) {
 		bson.M{"$inc": bson.M{"views": 1}},
 	)
 
-	c.JSON(http.StatusOK, artwork)
+	if artwork.AltText == "" {
+		artwork.AltText = fallbackAltText(&artwork)
+		artwork.AltTextGenerated = true
+	}
+	gateArtwork(&artwork, viewerMaturePreference(c, ctx))
+	if viewer, ok := optionalUserID(c); ok {
+		n, _ := database.Collection("likes").CountDocuments(
+			ctx,
+			bson.M{"artworkId": artwork.ID, "userId": viewer},
+			options.Count().SetLimit(1),
+		)
+		artwork.LikedByMe = n > 0
+	}
+
+	var artist models.User
+	database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": artwork.UserID},
+		options.FindOne().SetProjection(bson.M{"full_name": 1}),
+	).Decode(&artist)
+
+	// Page metadata is read by crawlers and link previews whatever the
+	// viewer's preference, so mature work only ever shares its blurred
+	// preview there.
+	shared := artwork
+	if models.IsMatureRating(artwork.ContentRating) {
+		shared.URL = artwork.BlurredURL
+	}
+
+	excerpt := utils.PlainTextExcerpt(artwork.DescriptionHTML, 160)
+	c.JSON(http.StatusOK, artworkPage{
+		Artwork: artwork,
+		SEO:     utils.GenerateSEO(artwork.Title, shared.URL, artwork.AltText, excerpt),
+		JSONLD:  utils.ArtworkJSONLD(&shared, artist.FullName, excerpt),
+	})
 }
 func GetMyArtworks(c *gin.Context) {
 	userIDValue, exists := c.Get("user_id")
@@ -189,48 +446,92 @@ func GetMyArtworks(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	opts := options.Find().
-		SetSort(bson.M{"createdAt": -1})
+	// ?order=portfolio is the older spelling of ?sort=portfolio, which
+	// returns the arranged portfolio order for editing.
+	defaultSort := sortNewest
+	if c.Query("order") == sortPortfolio {
+		defaultSort = sortPortfolio
+	}
+	page, err := parsePageRequest(c, defaultSort, sortNewest, sortViews, sortPortfolio)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	cursor, err := collection.Find(
-		ctx,
-		bson.M{
-			"userId": userID,
-		},
-		opts,
-	)
+	filter, err := listingFilter(c, activeArtworkFilter(bson.M{"userId": userID}))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if visibility := c.Query("visibility"); visibility != "" {
+		if !models.IsValidVisibility(visibility) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidVisibility.Error()})
+			return
+		}
+		filter["visibility"] = visibility
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "failed to fetch artworks",
 		})
 		return
 	}
-	defer cursor.Close(ctx)
 
+	// Pinned artworks lead the first page of the portfolio order and are
+	// left out of the pages themselves.
 	var artworks []models.Artwork
-	if err := cursor.All(ctx, &artworks); err != nil {
+	var pinned []primitive.ObjectID
+	if page.Sort == sortPortfolio {
+		var user models.User
+		database.Collection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+		if len(user.PinnedArtworks) > 0 {
+			if page.After == nil {
+				artworks, pinned, err = findPinned(ctx, collection, filter, user.PinnedArtworks)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{
+						"error": "failed to fetch artworks",
+					})
+					return
+				}
+			}
+			filter["_id"] = bson.M{"$nin": user.PinnedArtworks}
+		}
+	}
+
+	paged, next, err := findPage(ctx, collection, filter, page)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to parse artworks",
+			"error": "failed to fetch artworks",
 		})
 		return
 	}
+	artworks = append(artworks, paged...)
 
-	c.JSON(http.StatusOK, gin.H{
-		"count":    len(artworks),
-		"artworks": artworks,
-	})
+	owned := make([]ownerArtwork, len(artworks))
+	for i, a := range artworks {
+		owned[i] = ownerArtwork{Artwork: a, OriginalURL: a.OriginalURL}
+	}
+
+	response := pageResponse(owned, len(owned), next)
+	response["total"] = total
+	if page.Sort == sortPortfolio {
+		if pinned == nil {
+			pinned = []primitive.ObjectID{}
+		}
+		response["pinnedArtworkIds"] = pinned
+	}
+	c.JSON(http.StatusOK, response)
 }
-func GetPublicPortfolioByName(c *gin.Context) {
-	nameSlug := c.Param("name")
 
+// --- Helper: look up an artist from the name slug in a portfolio URL ---
+func findArtistByName(ctx context.Context, nameSlug string) (models.User, error) {
 	// FIX: Trim spaces and clean the slug from the URL
 	nameSlug = strings.TrimSpace(nameSlug)
 	normalizedName := strings.ReplaceAll(nameSlug, "-", " ")
 	searchName := strings.TrimSpace(normalizedName)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
 	userCollection := database.Collection("users")
 	var user models.User
 
@@ -245,38 +546,77 @@ func GetPublicPortfolioByName(c *gin.Context) {
 			},
 		},
 	).Decode(&user)
+	return user, err
+}
+
+func GetPublicPortfolioByName(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	page, err := parsePageRequest(c, sortPortfolio, sortPortfolio, sortNewest, sortViews)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := listingFilter(c, publicArtworkFilter(nil))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
+	user, err := findArtistByName(ctx, c.Param("name"))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "artist not found"})
 		return
 	}
+	filter["userId"] = user.ID
+	preference := viewerMaturePreference(c, ctx)
+	filter = matureContentFilter(filter, preference)
 
 	artworkCollection := database.Collection("artworks")
-	cursor, err := artworkCollection.Find(
-		ctx,
-		bson.M{
-			"userId":   user.ID,
-			"isPublic": true,
-		},
-		options.Find().SetSort(bson.M{"createdAt": -1}),
-	)
+	total, err := artworkCollection.CountDocuments(ctx, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var artworks []models.Artwork
-	if err := cursor.All(ctx, &artworks); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse artworks"})
-		return
+	// Pinned artworks lead the first page of the portfolio order and are
+	// left out of the pages themselves.
+	artworks := []models.Artwork{}
+	pinned := []primitive.ObjectID{}
+	if page.Sort == sortPortfolio && len(user.PinnedArtworks) > 0 {
+		if page.After == nil {
+			artworks, pinned, err = findPinned(ctx, artworkCollection, filter, user.PinnedArtworks)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+				return
+			}
+		}
+		filter["_id"] = bson.M{"$nin": user.PinnedArtworks}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"profile":  gin.H{"name": user.FullName},
-		"count":    len(artworks),
-		"artworks": artworks,
-	})
+	paged, next, err := findPage(ctx, artworkCollection, filter, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artworks"})
+		return
+	}
+	artworks = append(artworks, paged...)
+
+	fillAltText(artworks)
+	gateArtworks(artworks, preference)
+	markLikedByMe(c, ctx, artworks)
+
+	response := pageResponse(artworks, len(artworks), next)
+	response["profile"] = gin.H{
+		"id":             user.ID,
+		"name":           user.FullName,
+		"followersCount": user.FollowersCount,
+		"followingCount": user.FollowingCount,
+		"isFollowing":    isFollowing(c, ctx, user.ID),
+	}
+	response["total"] = total
+	response["pinnedArtworkIds"] = pinned
+	c.JSON(http.StatusOK, response)
 }
 func DeleteArtwork(c *gin.Context) {
 
@@ -314,31 +654,25 @@ func DeleteArtwork(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var artwork models.Artwork
-	err = collection.FindOne(ctx, bson.M{"_id": artworkID, "userId": userID}).Decode(&artwork)
+	// Deleting only moves the artwork to the trash; PurgeDeletedArtworks
+	// removes it for good once the retention period is over.
+	now := time.Now()
+	res, err := collection.UpdateOne(
+		ctx,
+		activeArtworkFilter(bson.M{"_id": artworkID, "userId": userID}),
+		bson.M{"$set": bson.M{"deletedAt": now}},
+	)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found or you do not own it"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete artwork"})
 		return
 	}
-
-	if config.Cloudinary != nil && artwork.PublicID != "" {
-		_, err = config.Cloudinary.Upload.Destroy(context.Background(), uploader.DestroyParams{
-			PublicID: artwork.PublicID,
-		})
-		if err != nil {
-			fmt.Println("Cloudinary deletion error:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete artwork from cloud storage"})
-			return
-		}
-	}
-
-	_, err = collection.DeleteOne(ctx, bson.M{"_id": artworkID, "userId": userID})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete artwork from database"})
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found or you do not own it"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "artwork deleted successfully",
+		"message": "artwork moved to trash",
+		"purgeAt": now.Add(trashRetention()),
 	})
 }
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"github.com/nerokome/artfolio-backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- Helper: load the current user's watermark settings (or defaults) ---
+func loadWatermarkSettings(ctx context.Context, userID primitive.ObjectID) (models.WatermarkSettings, error) {
+	var user models.User
+	err := database.Collection("users").FindOne(
+		ctx,
+		bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"watermark": 1}),
+	).Decode(&user)
+	if err != nil {
+		return models.WatermarkSettings{}, err
+	}
+	if user.Watermark == nil {
+		return models.DefaultWatermarkSettings(), nil
+	}
+	return *user.Watermark, nil
+}
+
+func GetWatermarkSettings(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	settings, err := loadWatermarkSettings(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func UpdateWatermarkSettings(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Enabled  *bool    `json:"enabled"`
+		Type     *string  `json:"type" binding:"omitempty,oneof=text logo"`
+		Text     *string  `json:"text" binding:"omitempty,max=80"`
+		Position *string  `json:"position"`
+		Opacity  *int     `json:"opacity" binding:"omitempty,min=1,max=100"`
+		Scale    *float64 `json:"scale" binding:"omitempty,min=0.05,max=1"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	settings, err := loadWatermarkSettings(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	if input.Enabled != nil {
+		settings.Enabled = *input.Enabled
+	}
+	if input.Type != nil {
+		settings.Type = *input.Type
+	}
+	if input.Text != nil {
+		settings.Text = strings.TrimSpace(*input.Text)
+	}
+	if input.Position != nil {
+		if _, ok := models.WatermarkPositions[*input.Position]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watermark position"})
+			return
+		}
+		settings.Position = *input.Position
+	}
+	if input.Opacity != nil {
+		settings.Opacity = *input.Opacity
+	}
+	if input.Scale != nil {
+		settings.Scale = *input.Scale
+	}
+
+	if settings.Enabled {
+		if settings.Type == models.WatermarkText && settings.Text == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "watermark text is required"})
+			return
+		}
+		if settings.Type == models.WatermarkLogo && settings.LogoPublicID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "upload a watermark logo first"})
+			return
+		}
+	}
+
+	_, err = database.Collection("users").UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{
+		"watermark":  settings,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save watermark settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UploadWatermarkLogo stores a logo used by logo watermarks. Any previous logo
+// is replaced.
+func UploadWatermarkLogo(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "file open failed"})
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "file read failed"})
+		return
+	}
+	// The declared type is the client's word; only real PNGs are stored, so
+	// no script-capable formats such as SVG reach the shared account.
+	if utils.SniffContentType(data) != "image/png" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "watermark logo must be a PNG image"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	settings, err := loadWatermarkSettings(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	result, err := uploadToCloudinary(ctx, data, uploader.UploadParams{Folder: "artfolio/watermarks"})
+	if err != nil {
+		fmt.Println("Cloudinary upload error:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload failed"})
+		return
+	}
+
+	previous := settings.LogoPublicID
+	settings.LogoPublicID = result.PublicID
+	settings.LogoURL = result.SecureURL
+	settings.Type = models.WatermarkLogo
+
+	_, err = database.Collection("users").UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{
+		"watermark":  settings,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		destroyAsset(ctx, result.PublicID, "")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save watermark settings"})
+		return
+	}
+
+	if previous != "" {
+		if err := destroyAsset(ctx, previous, ""); err != nil {
+			fmt.Println("Failed to remove old watermark logo:", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -28,6 +29,20 @@ func getUserID(c *gin.Context) (primitive.ObjectID, bool) {
 	return userID, true
 }
 
+// optionalUserID returns the signed-in user on routes where authentication is
+// optional.
+func optionalUserID(c *gin.Context) (primitive.ObjectID, bool) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		return primitive.NilObjectID, false
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return userID, true
+}
+
 
 func LogView(c *gin.Context) {
 	viewCollection := database.Collection("view_events")
@@ -48,6 +63,18 @@ func LogView(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Only artworks a visitor could be looking at are counted; anything else
+	// would leave view events behind for trashed, private or unknown ids.
+	exists, err := artworkCollection.CountDocuments(ctx, linkableArtworkFilter(bson.M{"_id": objID}), options.Count().SetLimit(1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to log view event"})
+		return
+	}
+	if exists == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artwork not found"})
+		return
+	}
+
 	var viewerID *primitive.ObjectID
 	if userIDStr, exists := c.Get("user_id"); exists {
 		if id, err := primitive.ObjectIDFromHex(userIDStr.(string)); err == nil {
@@ -69,7 +96,7 @@ func LogView(c *gin.Context) {
 
 	_, err = artworkCollection.UpdateOne(
 		ctx,
-		bson.M{"_id": objID},
+		linkableArtworkFilter(bson.M{"_id": objID}),
 		bson.M{"$inc": bson.M{"views": 1}},
 	)
 	if err != nil {
@@ -91,12 +118,32 @@ func GetAnalyticsOverview(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	totalArtworks, _ := artworkCollection.CountDocuments(ctx, bson.M{"userId": userID})
-	totalViews, _ := viewCollection.CountDocuments(ctx, bson.M{"artworkId": bson.M{"$in": artworkIDsByUser(ctx, userID)}})
+	ids, err := artworkIDsByUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load analytics"})
+		return
+	}
+	byArtwork := bson.M{"artworkId": bson.M{"$in": ids}}
+
+	totalArtworks, err := artworkCollection.CountDocuments(ctx, activeArtworkFilter(bson.M{"userId": userID}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load analytics"})
+		return
+	}
+	totalViews, err := viewCollection.CountDocuments(ctx, byArtwork)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load analytics"})
+		return
+	}
+	totalLikes, err := database.Collection("likes").CountDocuments(ctx, byArtwork)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load analytics"})
+		return
+	}
 
 	cursor, err := viewCollection.Aggregate(ctx, bson.A{
 		bson.M{
-			"$match": bson.M{"artworkId": bson.M{"$in": artworkIDsByUser(ctx, userID)}},
+			"$match": byArtwork,
 		},
 		bson.M{
 			"$group": bson.M{
@@ -111,14 +158,20 @@ func GetAnalyticsOverview(c *gin.Context) {
 			},
 		},
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load analytics"})
+		return
+	}
 	var viewerSplit []bson.M
-	if err == nil {
-		cursor.All(ctx, &viewerSplit)
+	if err := cursor.All(ctx, &viewerSplit); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load analytics"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"totalArtworks": totalArtworks,
 		"totalViews":    totalViews,
+		"totalLikes":    totalLikes,
 		"viewerSplit":   viewerSplit,
 	})
 }
@@ -130,36 +183,78 @@ func GetViewsOverTime(c *gin.Context) {
 		return
 	}
 
-	viewCollection := database.Collection("view_events")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	start := time.Now().AddDate(0, 0, -6)
+	ids, err := artworkIDsByUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregation failed"})
+		return
+	}
 
-	cursor, err := viewCollection.Aggregate(ctx, bson.A{
+	views, err := countByDay(ctx, "view_events", ids, start)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregation failed"})
+		return
+	}
+	likes, err := countByDay(ctx, "likes", ids, start)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregation failed"})
+		return
+	}
+
+	var days []string
+	for day := range views {
+		days = append(days, day)
+	}
+	for day := range likes {
+		if _, ok := views[day]; !ok {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+
+	result := make([]gin.H, len(days))
+	for i, day := range days {
+		result[i] = gin.H{"_id": day, "views": views[day], "likes": likes[day]}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// countByDay counts the events in an engagement collection for the given
+// artworks per calendar day since start.
+func countByDay(ctx context.Context, collection string, artworkIDs []primitive.ObjectID, start time.Time) (map[string]int, error) {
+	cursor, err := database.Collection(collection).Aggregate(ctx, bson.A{
 		bson.M{
 			"$match": bson.M{
-				"artworkId": bson.M{"$in": artworkIDsByUser(ctx, userID)},
+				"artworkId": bson.M{"$in": artworkIDs},
 				"createdAt": bson.M{"$gte": start},
 			},
 		},
 		bson.M{
 			"$group": bson.M{
 				"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$createdAt"}},
-				"views": bson.M{"$sum": 1},
+				"count": bson.M{"$sum": 1},
 			},
 		},
-		bson.M{"$sort": bson.M{"_id": 1}},
 	})
-
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregation failed"})
-		return
+		return nil, err
 	}
 
-	var result []bson.M
-	cursor.All(ctx, &result)
-	c.JSON(http.StatusOK, result)
+	var rows []struct {
+		Day   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Day] = row.Count
+	}
+	return counts, nil
 }
 
 // --- Most viewed artworks (per-user) ---
@@ -174,7 +269,7 @@ func GetMostViewedArtworks(c *gin.Context) {
 	defer cancel()
 
 	opts := options.Find().SetSort(bson.M{"views": -1})
-	cursor, err := collection.Find(ctx, bson.M{"isPublic": true, "userId": userID}, opts)
+	cursor, err := collection.Find(ctx, publicArtworkFilter(bson.M{"userId": userID}), opts)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fetch failed"})
@@ -197,9 +292,15 @@ func GetEngagementSplit(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	ids, err := artworkIDsByUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "aggregation failed"})
+		return
+	}
+
 	pipeline := bson.A{
 		bson.M{
-			"$match": bson.M{"artworkId": bson.M{"$in": artworkIDsByUser(ctx, userID)}},
+			"$match": bson.M{"artworkId": bson.M{"$in": ids}},
 		},
 		bson.M{
 			"$group": bson.M{
@@ -221,6 +322,7 @@ func GetEngagementSplit(c *gin.Context) {
 				"_id":   1,
 				"title": "$artwork.title",
 				"views": 1,
+				"likes": bson.M{"$ifNull": bson.A{"$artwork.likes", 0}},
 			},
 		},
 		bson.M{"$sort": bson.M{"views": -1}},
@@ -244,22 +346,28 @@ func GetEngagementSplit(c *gin.Context) {
 }
 
 // --- Helper: fetch artwork IDs for a user ---
-func artworkIDsByUser(ctx context.Context, userID primitive.ObjectID) []primitive.ObjectID {
+func artworkIDsByUser(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
 	collection := database.Collection("artworks")
-	cursor, err := collection.Find(ctx, bson.M{"userId": userID})
+	cursor, err := collection.Find(
+		ctx,
+		activeArtworkFilter(bson.M{"userId": userID}),
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
 	if err != nil {
-		return []primitive.ObjectID{}
+		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var artworks []struct {
 		ID primitive.ObjectID `bson:"_id"`
 	}
-	cursor.All(ctx, &artworks)
+	if err := cursor.All(ctx, &artworks); err != nil {
+		return nil, err
+	}
 
 	ids := make([]primitive.ObjectID, len(artworks))
 	for i, art := range artworks {
 		ids[i] = art.ID
 	}
-	return ids
+	return ids, nil
 }
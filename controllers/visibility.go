@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	errInvalidVisibility = errors.New("visibility must be one of draft, private, unlisted, public, scheduled")
+	errPublishAtRequired = errors.New("publishAt must be a future time for scheduled artworks")
+	errAlreadyPublished  = errors.New("a published artwork can't go back to draft")
+)
+
+// activeArtworkFilter narrows filter to artworks that aren't in the trash.
+// A nil filter matches every active artwork.
+func activeArtworkFilter(filter bson.M) bson.M {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter["deletedAt"] = nil
+	return filter
+}
+
+// publicArtworkFilter narrows filter to artworks that belong in public
+// listings.
+func publicArtworkFilter(filter bson.M) bson.M {
+	filter = activeArtworkFilter(filter)
+	filter["visibility"] = models.VisibilityPublic
+	return filter
+}
+
+// linkableArtworkFilter narrows filter to artworks anyone can open by direct
+// link, which includes unlisted ones.
+func linkableArtworkFilter(filter bson.M) bson.M {
+	filter = activeArtworkFilter(filter)
+	filter["visibility"] = bson.M{"$in": []string{models.VisibilityPublic, models.VisibilityUnlisted}}
+	return filter
+}
+
+// validateVisibility checks a requested state on its own; transition rules
+// that depend on the artwork's history live in visibilityUpdate.
+func validateVisibility(visibility string, publishAt *time.Time) error {
+	if !models.IsValidVisibility(visibility) {
+		return errInvalidVisibility
+	}
+	if visibility == models.VisibilityScheduled && (publishAt == nil || !publishAt.After(time.Now())) {
+		return errPublishAtRequired
+	}
+	return nil
+}
+
+// parsePublishAt reads an optional RFC 3339 timestamp.
+func parsePublishAt(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("publishAt must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
+
+// requestedVisibility reads the visibility and publishAt a client sent for a
+// new artwork, defaulting to public, and validates them together.
+func requestedVisibility(visibility, publishAt string) (string, *time.Time, error) {
+	visibility = strings.ToLower(strings.TrimSpace(visibility))
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+	}
+	at, err := parsePublishAt(strings.TrimSpace(publishAt))
+	if err != nil {
+		return "", nil, err
+	}
+	if err := validateVisibility(visibility, at); err != nil {
+		return "", nil, err
+	}
+	return visibility, at, nil
+}
+
+// applyVisibility sets the visibility fields of a new artwork.
+func applyVisibility(artwork *models.Artwork, visibility string, publishAt *time.Time) {
+	artwork.Visibility = visibility
+	artwork.IsPublic = visibility == models.VisibilityPublic
+	if visibility == models.VisibilityScheduled {
+		artwork.PublishAt = publishAt
+	}
+	if artwork.IsPublic {
+		now := time.Now()
+		artwork.PublishedAt = &now
+	}
+}
+
+// visibilityUpdate returns the update document that moves an existing
+// artwork to a new visibility state.
+func visibilityUpdate(artwork *models.Artwork, visibility string, publishAt *time.Time) (bson.M, bson.M, error) {
+	if err := validateVisibility(visibility, publishAt); err != nil {
+		return nil, nil, err
+	}
+	if visibility == models.VisibilityDraft && artwork.PublishedAt != nil {
+		return nil, nil, errAlreadyPublished
+	}
+
+	set := bson.M{
+		"visibility": visibility,
+		"isPublic":   visibility == models.VisibilityPublic,
+	}
+	unset := bson.M{}
+
+	if visibility == models.VisibilityScheduled {
+		set["publishAt"] = publishAt
+	} else {
+		unset["publishAt"] = ""
+	}
+	if visibility == models.VisibilityPublic && artwork.PublishedAt == nil {
+		set["publishedAt"] = time.Now()
+	}
+	return set, unset, nil
+}
+
+// PublishScheduledArtworks flips scheduled artworks live once their publishAt
+// has passed, checking every 30 seconds.
+func PublishScheduledArtworks() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		res, err := database.Collection("artworks").UpdateMany(
+			ctx,
+			activeArtworkFilter(bson.M{
+				"visibility": models.VisibilityScheduled,
+				"publishAt":  bson.M{"$lte": time.Now()},
+			}),
+			bson.A{bson.M{"$set": bson.M{
+				"visibility":  models.VisibilityPublic,
+				"isPublic":    true,
+				"publishedAt": bson.M{"$ifNull": bson.A{"$publishedAt", "$publishAt"}},
+			}}},
+		)
+		cancel()
+
+		if err != nil {
+			fmt.Println("Scheduled publishing failed:", err)
+		} else if res.ModifiedCount > 0 {
+			fmt.Println("Published", res.ModifiedCount, "scheduled artworks")
+		}
+
+		time.Sleep(30 * time.Second)
+	}
+}
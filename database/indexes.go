@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the indexes the API relies on. CreateMany is a no-op
+// for indexes that already exist, so this is safe to run on every start.
+func EnsureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := map[string][]mongo.IndexModel{
+		"artworks": {
+			{Keys: bson.D{{Key: "hashBands", Value: 1}}},
+			{Keys: bson.D{{Key: "visibility", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "visibility", Value: 1}, {Key: "views", Value: -1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "views", Value: -1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "visibility", Value: 1}, {Key: "publishedAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "visibility", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "visibility", Value: 1}, {Key: "publishAt", Value: 1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "position", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "tags", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "medium", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "category", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "deletedAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+			{Keys: bson.D{{Key: "viewMilestone", Value: 1}, {Key: "views", Value: 1}}},
+			{Keys: bson.D{{Key: "searchWords", Value: 1}}},
+			{
+				Keys: bson.D{{Key: "title", Value: "text"}, {Key: "tags", Value: "text"}, {Key: "description", Value: "text"}},
+				Options: options.Index().
+					SetName("artwork_search").
+					SetWeights(bson.M{"title": 10, "tags": 5, "description": 1}),
+			},
+		},
+		"users": {
+			{Keys: bson.D{{Key: "full_name", Value: "text"}}, Options: options.Index().SetName("artist_search")},
+			{Keys: bson.D{{Key: "search_words", Value: 1}}},
+		},
+		"follows": {
+			{Keys: bson.D{{Key: "followerId", Value: 1}, {Key: "followingId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "followerId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "followingId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"likes": {
+			{Keys: bson.D{{Key: "artworkId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		},
+		"comments": {
+			{Keys: bson.D{{Key: "artworkId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		"notifications": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "read", Value: 1}}},
+			// Old notifications expire after 90 days.
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(90 * 24 * 60 * 60)},
+		},
+		"collections": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "artworkIds", Value: 1}}},
+		},
+		"upload_sessions": {
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}},
+		},
+		"upload_intents": {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expiresAt", Value: 1}}},
+		},
+		"upload_jobs": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}}},
+		},
+		"view_events": {
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		},
+		"trending_artworks": {
+			{Keys: bson.D{{Key: "generation", Value: 1}, {Key: "rank", Value: 1}}},
+		},
+		"related_artworks": {
+			{Keys: bson.D{{Key: "computedAt", Value: 1}}},
+		},
+		"moderation_flags": {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}},
+		},
+	}
+
+	for name, models := range indexes {
+		if _, err := Collection(name).Indexes().CreateMany(ctx, models); err != nil {
+			log.Println("Failed to create indexes for", name+":", err)
+		}
+	}
+}
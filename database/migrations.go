@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// RunMigrations backfills fields added after documents were first written.
+// Every step only touches documents that still need it, so it is safe to run
+// on every start.
+func RunMigrations() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	steps := []struct {
+		name       string
+		collection string
+		filter     bson.M
+		update     interface{}
+	}{
+		{
+			name:       "artwork media type",
+			collection: "artworks",
+			filter:     bson.M{"mediaType": bson.M{"$exists": false}},
+			update:     bson.M{"$set": bson.M{"mediaType": "image"}},
+		},
+		{
+			// Artworks from before visibility states only had isPublic.
+			name:       "artwork visibility",
+			collection: "artworks",
+			filter:     bson.M{"visibility": bson.M{"$exists": false}},
+			update: bson.A{bson.M{"$set": bson.M{
+				"visibility": bson.M{"$cond": bson.A{"$isPublic", "public", "private"}},
+				"publishedAt": bson.M{"$cond": bson.A{
+					"$isPublic",
+					bson.M{"$ifNull": bson.A{"$publishedAt", "$createdAt"}},
+					"$$REMOVE",
+				}},
+			}}},
+		},
+		{
+			// Single-file artworks become a one-item media list whose item
+			// reuses the artwork id.
+			name:       "artwork media list",
+			collection: "artworks",
+			filter:     bson.M{"media": bson.M{"$exists": false}},
+			update: bson.A{bson.M{"$set": bson.M{
+				"coverMediaId": "$_id",
+				"media": bson.A{bson.M{
+					"_id":              "$_id",
+					"kind":             "main",
+					"mediaType":        "$mediaType",
+					"url":              "$url",
+					"publicId":         "$publicId",
+					"width":            "$width",
+					"height":           "$height",
+					"duration":         "$duration",
+					"posterUrl":        "$posterUrl",
+					"watermarked":      "$watermarked",
+					"originalUrl":      "$originalUrl",
+					"originalPublicId": "$originalPublicId",
+					"createdAt":        "$createdAt",
+				}},
+			}}},
+		},
+		{
+			// Existing artworks start from the milestone they already
+			// passed, so nobody is notified about old view counts.
+			name:       "artwork view milestone",
+			collection: "artworks",
+			filter:     bson.M{"viewMilestone": bson.M{"$exists": false}},
+			update: bson.A{bson.M{"$set": bson.M{
+				"viewMilestone": bson.M{"$switch": bson.M{
+					"branches": milestoneBranches(),
+					"default":  0,
+				}},
+			}}},
+		},
+	}
+
+	for _, s := range steps {
+		res, err := Collection(s.collection).UpdateMany(ctx, s.filter, s.update)
+		if err != nil {
+			log.Println("Migration failed:", s.name+":", err)
+			continue
+		}
+		if res.ModifiedCount > 0 {
+			log.Printf("Migration %q updated %d documents\n", s.name, res.ModifiedCount)
+		}
+	}
+}
+
+// milestoneBranches maps a view count to the highest milestone it reached,
+// checking the largest first.
+func milestoneBranches() bson.A {
+	branches := bson.A{}
+	for i := len(models.ViewMilestones) - 1; i >= 0; i-- {
+		m := models.ViewMilestones[i]
+		branches = append(branches, bson.M{"case": bson.M{"$gte": bson.A{"$views", m}}, "then": m})
+	}
+	return branches
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -9,6 +11,7 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/nerokome/artfolio-backend/config"
+	"github.com/nerokome/artfolio-backend/controllers"
 	"github.com/nerokome/artfolio-backend/database"
 	"github.com/nerokome/artfolio-backend/routes"
 )
@@ -21,9 +24,33 @@ func main() {
 
 	config.InitCloudinary()
 	database.ConnectMongo()
+	database.EnsureIndexes()
+	database.RunMigrations()
+	controllers.InitSearch()
+
+	// Background workers. Each loops for the life of the process and logs
+	// its own failures.
+	go controllers.CleanupUploadSessions()
+	go controllers.CleanupUploadIntents()
+	go controllers.ProcessUploadJobs()
+	go controllers.PublishScheduledArtworks()
+	go controllers.PurgeDeletedArtworks()
+	go controllers.RefreshSearchIndex()
+	go controllers.RefreshTrendingScores()
+	go controllers.RefreshRelatedArtworks()
+	go controllers.BackfillPalettes()
+	go controllers.CheckViewMilestones()
+	go controllers.RecountEngagement()
+	go controllers.GenerateBlurredPreviews()
 
 	r := gin.Default()
 
+	// ClientIP, which rate limits are keyed by, only reads X-Forwarded-For
+	// from the proxies listed in TRUSTED_PROXIES (comma-separated).
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
 	r.Use(cors.New(cors.Config{
 		AllowOrigins: []string{
 			"http://localhost:3000",
@@ -36,9 +63,18 @@ func main() {
 			"Origin",
 			"Content-Type",
 			"Authorization",
+			"Tus-Resumable",
+			"Upload-Length",
+			"Upload-Metadata",
+			"Upload-Offset",
 		},
 		ExposeHeaders: []string{
 			"Content-Length",
+			"Location",
+			"Tus-Resumable",
+			"Upload-Expires",
+			"Upload-Length",
+			"Upload-Offset",
 		},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -52,8 +88,26 @@ func main() {
 	routes.ArtworkRoutes(r)
 	routes.AnalyticsRoutes(r)
 	routes.PublicPortfolioRoutes(r)
+	routes.ModerationRoutes(r)
+	routes.UserRoutes(r)
+	routes.CollectionRoutes(r)
+	routes.TagRoutes(r)
+	routes.SearchRoutes(r)
+	routes.FeedRoutes(r)
+	routes.CommentRoutes(r)
+	routes.NotificationRoutes(r)
 
 	if err := r.Run(":5005"); err != nil {
 		log.Fatal("Server failed to start:", err)
 	}
 }
+
+func trustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
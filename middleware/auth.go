@@ -32,20 +32,61 @@ func Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		var userID string
-		if id, ok := claims["user_id"].(string); ok && id != "" {
-			userID = id
-		} else if id, ok := claims["userId"].(string); ok && id != "" {
-			userID = id
-		}
-
-		if userID == "" {
+		if !setClaims(c, claims) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id missing in token"})
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		c.Set("user_id", userID)
+// OptionalAuthenticate identifies the viewer when a valid token is sent and
+// otherwise lets the request through anonymously, for public endpoints whose
+// output depends on who is asking.
+func OptionalAuthenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := utils.ValidateToken(parts[1]); err == nil {
+				setClaims(c, claims)
+			}
+		}
 		c.Next()
 	}
 }
+
+func setClaims(c *gin.Context, claims map[string]interface{}) bool {
+	var userID string
+	if id, ok := claims["user_id"].(string); ok && id != "" {
+		userID = id
+	} else if id, ok := claims["userId"].(string); ok && id != "" {
+		userID = id
+	}
+
+	if userID == "" {
+		return false
+	}
+
+	c.Set("user_id", userID)
+	if role, ok := claims["role"].(string); ok {
+		c.Set("role", role)
+	}
+	return true
+}
+
+// RequireRole must run after Authenticate.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		c.Abort()
+	}
+}
@@ -2,7 +2,6 @@ package middleware
 
 import (
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
@@ -15,30 +14,64 @@ type client struct {
 	lastSeen time.Time
 }
 
-var (
-	clients = make(map[string]*client)
+// limiterSet holds the token buckets of one rate limiter, one per key.
+// Every route gets its own set, so a busy route can't use up another's
+// allowance.
+type limiterSet struct {
+	rate    rate.Limit
+	burst   int
 	mu      sync.Mutex
-)
+	clients map[string]*client
+}
 
-func RateLimiter(rateLimit rate.Limit, burst int) gin.HandlerFunc {
-	go cleanupClients()
+func (s *limiterSet) allow(key string) bool {
+	s.mu.Lock()
+	cl, exists := s.clients[key]
+	if !exists {
+		cl = &client{limiter: rate.NewLimiter(s.rate, s.burst)}
+		s.clients[key] = cl
+	}
+	cl.lastSeen = time.Now()
+	s.mu.Unlock()
 
-	return func(c *gin.Context) {
-		ip := getClientIP(c)
+	return cl.limiter.Allow()
+}
 
-		mu.Lock()
-		cl, exists := clients[ip]
-		if !exists {
-			cl = &client{
-				limiter:  rate.NewLimiter(rateLimit, burst),
-				lastSeen: time.Now(),
+func (s *limiterSet) cleanup() {
+	for {
+		time.Sleep(time.Minute)
+		s.mu.Lock()
+		for key, cl := range s.clients {
+			if time.Since(cl.lastSeen) > 10*time.Minute {
+				delete(s.clients, key)
 			}
-			clients[ip] = cl
 		}
-		cl.lastSeen = time.Now()
-		mu.Unlock()
+		s.mu.Unlock()
+	}
+}
+
+// RateLimiter limits each client IP to rateLimit requests per second with
+// bursts of burst. The IP comes from gin's ClientIP, which only trusts
+// forwarding headers set by the configured trusted proxies.
+func RateLimiter(rateLimit rate.Limit, burst int) gin.HandlerFunc {
+	return RateLimiterBy(rateLimit, burst, func(c *gin.Context) string {
+		return c.ClientIP()
+	})
+}
 
-		if !cl.limiter.Allow() {
+// RateLimiterBy is RateLimiter keyed by key(c) instead of the client IP.
+// Requests for which key returns "" are keyed by IP.
+func RateLimiterBy(rateLimit rate.Limit, burst int, key func(*gin.Context) string) gin.HandlerFunc {
+	set := &limiterSet{rate: rateLimit, burst: burst, clients: make(map[string]*client)}
+	go set.cleanup()
+
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			k = "ip:" + c.ClientIP()
+		}
+
+		if !set.allow(k) {
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"error": "Too many requests. Slow down.",
 			})
@@ -49,27 +82,11 @@ func RateLimiter(rateLimit rate.Limit, burst int) gin.HandlerFunc {
 	}
 }
 
-func getClientIP(c *gin.Context) string {
-	ip := c.GetHeader("X-Forwarded-For")
-	if ip != "" {
-		ip = strings.Split(ip, ",")[0]
-		ip = strings.TrimSpace(ip)
-	}
-	if ip == "" {
-		ip = c.ClientIP()
-	}
-	return ip
-}
-
-func cleanupClients() {
-	for {
-		time.Sleep(time.Minute)
-		mu.Lock()
-		for ip, cl := range clients {
-			if time.Since(cl.lastSeen) > 10*time.Minute {
-				delete(clients, ip)
-			}
-		}
-		mu.Unlock()
+// UserKey keys a limiter by the user Authenticate identified, so one
+// account gets one allowance whichever addresses it posts from.
+func UserKey(c *gin.Context) string {
+	if id := c.GetString("user_id"); id != "" {
+		return "user:" + id
 	}
+	return ""
 }
@@ -1,13 +1,93 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func UploadMiddleware(maxFileSizeMB int64, allowedTypes []string) gin.HandlerFunc {
+// Limits for artwork images. Resumable uploads exist for files larger than
+// a single request comfortably carries, so they get a higher cap.
+const (
+	ArtworkMaxUploadMB   = 10
+	ResumableMaxUploadMB = 100
+)
+
+var ArtworkAllowedTypes = []string{"image/"}
+
+// TypeLimit caps the size of files whose content type starts with Prefix.
+type TypeLimit struct {
+	Prefix    string
+	MaxSizeMB int64
+}
+
+// Animations and turntables are uploaded as short videos, which get a
+// separate, larger limit than still images.
+const (
+	VideoMaxUploadMB = 100
+	VideoMaxSeconds  = 90
+)
+
+var ArtworkMediaLimits = []TypeLimit{
+	{Prefix: "image/", MaxSizeMB: ArtworkMaxUploadMB},
+	{Prefix: "video/mp4", MaxSizeMB: VideoMaxUploadMB},
+	{Prefix: "video/webm", MaxSizeMB: VideoMaxUploadMB},
+	{Prefix: "video/quicktime", MaxSizeMB: VideoMaxUploadMB},
+}
+
+// Bulk uploads arrive as a single ZIP archive.
+const BulkMaxArchiveMB = 500
+
+var BulkArchiveTypes = []string{"application/zip", "application/x-zip-compressed", "multipart/x-zip"}
+
+var (
+	ErrFileEmpty       = errors.New("file is empty")
+	ErrFileTooLarge    = errors.New("file too large")
+	ErrInvalidFileType = errors.New("invalid file type")
+)
+
+// ValidateFile applies the same checks as UploadMiddleware to a file that
+// didn't arrive as a multipart form field (chunked uploads, archives, ...).
+func ValidateFile(size int64, contentType string, maxFileSizeMB int64, allowedTypes []string) error {
+	if size == 0 {
+		return ErrFileEmpty
+	}
+
+	if size > maxFileSizeMB*1024*1024 {
+		return ErrFileTooLarge
+	}
+
+	for _, t := range allowedTypes {
+		if strings.HasPrefix(contentType, t) {
+			return nil
+		}
+	}
+	return ErrInvalidFileType
+}
+
+// ValidateMedia is ValidateFile with a size limit chosen by content type.
+func ValidateMedia(size int64, contentType string, limits []TypeLimit) error {
+	for _, l := range limits {
+		if strings.HasPrefix(contentType, l.Prefix) {
+			return ValidateFile(size, contentType, l.MaxSizeMB, []string{l.Prefix})
+		}
+	}
+	return ErrInvalidFileType
+}
+
+func limitPrefixes(limits []TypeLimit) []string {
+	prefixes := make([]string, len(limits))
+	for i, l := range limits {
+		prefixes[i] = l.Prefix
+	}
+	return prefixes
+}
+
+// MediaUploadMiddleware is UploadMiddleware for endpoints that accept several
+// kinds of media with different size limits.
+func MediaUploadMiddleware(limits []TypeLimit) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		file, err := c.FormFile("file")
 		if err != nil {
@@ -16,33 +96,45 @@ func UploadMiddleware(maxFileSizeMB int64, allowedTypes []string) gin.HandlerFun
 			return
 		}
 
-		if file.Size == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "file is empty"})
+		contentType := file.Header.Get("Content-Type")
+		if err := ValidateMedia(file.Size, contentType, limits); err != nil {
+			if errors.Is(err, ErrInvalidFileType) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":        "invalid file type",
+					"fileType":     contentType,
+					"allowedTypes": limitPrefixes(limits),
+				})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			}
 			c.Abort()
 			return
 		}
 
-		if file.Size > maxFileSizeMB*1024*1024 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "file too large"})
+		c.Next()
+	}
+}
+
+func UploadMiddleware(maxFileSizeMB int64, allowedTypes []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
 			c.Abort()
 			return
 		}
 
 		contentType := file.Header.Get("Content-Type")
-		validType := false
-		for _, t := range allowedTypes {
-			if strings.HasPrefix(contentType, t) {
-				validType = true
-				break
+		if err := ValidateFile(file.Size, contentType, maxFileSizeMB, allowedTypes); err != nil {
+			if errors.Is(err, ErrInvalidFileType) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":        "invalid file type",
+					"fileType":     contentType,
+					"allowedTypes": allowedTypes,
+				})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			}
-		}
-
-		if !validType {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        "invalid file type",
-				"fileType":     contentType,
-				"allowedTypes": allowedTypes,
-			})
 			c.Abort()
 			return
 		}
@@ -6,14 +6,121 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Visibility states. Unlisted artworks are reachable by direct link but kept
+// out of listings; scheduled ones become public at PublishAt.
+const (
+	VisibilityDraft     = "draft"
+	VisibilityPrivate   = "private"
+	VisibilityUnlisted  = "unlisted"
+	VisibilityPublic    = "public"
+	VisibilityScheduled = "scheduled"
+)
+
+func IsValidVisibility(v string) bool {
+	switch v {
+	case VisibilityDraft, VisibilityPrivate, VisibilityUnlisted, VisibilityPublic, VisibilityScheduled:
+		return true
+	}
+	return false
+}
+
+const (
+	MediaTypeImage    = "image"
+	MediaTypeAnimated = "animated"
+	MediaTypeVideo    = "video"
+)
+
 type Artwork struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
 	Title     string             `bson:"title" json:"title"`
 	Slug      string             `bson:"slug" json:"slug"`
+	Tags      []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	Medium    string             `bson:"medium,omitempty" json:"medium,omitempty"`
+	Category  string             `bson:"category,omitempty" json:"category,omitempty"`
+	MediaType string             `bson:"mediaType" json:"mediaType"`
 	URL       string             `bson:"url" json:"url"`
 	PublicID  string             `bson:"publicId" json:"publicId"`
+	Width     int                `bson:"width,omitempty" json:"width,omitempty"`
+	Height    int                `bson:"height,omitempty" json:"height,omitempty"`
 	Views     int                `bson:"views" json:"views"`
-	IsPublic  bool               `bson:"isPublic" json:"isPublic"`
+	Likes     int                `bson:"likes" json:"likes"`
 	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+
+	// SearchWords are the lowercased words of Title, matched by title
+	// suggestions.
+	SearchWords []string `bson:"searchWords" json:"-"`
+
+	// Description is the artist's Markdown source; DescriptionHTML is the
+	// sanitized rendering served to clients.
+	Description     string `bson:"description,omitempty" json:"description,omitempty"`
+	DescriptionHTML string `bson:"descriptionHtml,omitempty" json:"descriptionHtml,omitempty"`
+
+	// AltText describes the image for screen readers. AltTextGenerated is set
+	// on responses where it was filled in from the title and metadata.
+	AltText          string `bson:"altText,omitempty" json:"altText,omitempty"`
+	AltTextGenerated bool   `bson:"-" json:"altTextGenerated,omitempty"`
+
+	// LikedByMe is set on responses when the signed-in viewer likes the
+	// artwork.
+	LikedByMe bool `bson:"-" json:"likedByMe,omitempty"`
+
+	License *License `bson:"license,omitempty" json:"license,omitempty"`
+
+	// CommentsDisabled stops new comments; existing ones stay visible.
+	CommentsDisabled bool `bson:"commentsDisabled,omitempty" json:"commentsDisabled,omitempty"`
+
+	ContentRating   string   `bson:"contentRating,omitempty" json:"contentRating,omitempty"`
+	ContentWarnings []string `bson:"contentWarnings,omitempty" json:"contentWarnings,omitempty"`
+	// BlurredURL is the blurred preview of the cover, stored as an asset of
+	// its own for mature artworks. Blurred is set on responses for gated
+	// artworks: the client shows the blurred preview first when it is true.
+	BlurredURL      string `bson:"blurredUrl,omitempty" json:"blurredUrl,omitempty"`
+	BlurredPublicID string `bson:"blurredPublicId,omitempty" json:"-"`
+	Blurred         bool   `bson:"-" json:"blurred,omitempty"`
+
+	Visibility  string     `bson:"visibility" json:"visibility"`
+	PublishAt   *time.Time `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+	PublishedAt *time.Time `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+
+	// ViewMilestone is the highest of ViewMilestones the artist has been
+	// notified of.
+	ViewMilestone int `bson:"viewMilestone" json:"-"`
+
+	// Position is the artist's manual portfolio order. Artworks without one
+	// sort ahead of arranged work, newest first.
+	Position *int `bson:"position,omitempty" json:"position,omitempty"`
+
+	// DeletedAt is set while the artwork sits in the trash.
+	DeletedAt *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	// IsPublic mirrors Visibility == public for clients and documents that
+	// predate visibility states. Always write it together with Visibility.
+	IsPublic bool `bson:"isPublic" json:"isPublic"`
+
+	// Media holds every file of the artwork in display order. The top-level
+	// media fields above mirror the cover item so listings only need them.
+	Media        []MediaItem        `bson:"media,omitempty" json:"media,omitempty"`
+	CoverMediaID primitive.ObjectID `bson:"coverMediaId,omitempty" json:"coverMediaId,omitempty"`
+
+	// Video-only fields. Loop marks turntables and other pieces meant to
+	// play on repeat.
+	Duration  float64 `bson:"duration,omitempty" json:"duration,omitempty"`
+	PosterURL string  `bson:"posterUrl,omitempty" json:"posterUrl,omitempty"`
+	Loop      bool    `bson:"loop,omitempty" json:"loop,omitempty"`
+
+	// Perceptual hash of the uploaded image (hex dHash) and its band keys,
+	// used to find near-duplicates by Hamming distance.
+	PerceptualHash string   `bson:"perceptualHash,omitempty" json:"perceptualHash,omitempty"`
+	HashBands      []string `bson:"hashBands,omitempty" json:"-"`
+
+	// Palette holds the image's dominant colours as "#rrggbb", most
+	// prominent first.
+	Palette []string `bson:"palette,omitempty" json:"palette,omitempty"`
+
+	// When the owner has watermarking enabled, URL/PublicID point at the
+	// watermarked public variant and the clean original is stored privately.
+	// The original is never serialized; owner endpoints expose it explicitly.
+	Watermarked      bool   `bson:"watermarked,omitempty" json:"watermarked,omitempty"`
+	OriginalURL      string `bson:"originalUrl,omitempty" json:"-"`
+	OriginalPublicID string `bson:"originalPublicId,omitempty" json:"-"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const MaxArtworksPerCollection = 500
+
+// Collection groups an artist's artworks into an ordered series. Visibility
+// is public, unlisted or private; artworks inside still follow their own
+// visibility.
+type Collection struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	UserID         primitive.ObjectID   `bson:"userId" json:"userId"`
+	Title          string               `bson:"title" json:"title"`
+	Slug           string               `bson:"slug" json:"slug"`
+	Description    string               `bson:"description,omitempty" json:"description,omitempty"`
+	CoverArtworkID *primitive.ObjectID  `bson:"coverArtworkId,omitempty" json:"coverArtworkId,omitempty"`
+	Visibility     string               `bson:"visibility" json:"visibility"`
+	ArtworkIDs     []primitive.ObjectID `bson:"artworkIds" json:"artworkIds"`
+	CreatedAt      time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+func IsValidCollectionVisibility(v string) bool {
+	switch v {
+	case VisibilityPrivate, VisibilityUnlisted, VisibilityPublic:
+		return true
+	}
+	return false
+}
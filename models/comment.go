@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxCommentDepth is how deeply replies can nest; top-level comments have
+// depth 0.
+const MaxCommentDepth = 3
+
+// Comment is a plain-text comment on an artwork. Replies point at their
+// parent; top-level comments have no ParentID.
+type Comment struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ArtworkID primitive.ObjectID  `bson:"artworkId" json:"artworkId"`
+	UserID    primitive.ObjectID  `bson:"userId" json:"userId"`
+	ParentID  *primitive.ObjectID `bson:"parentId" json:"parentId"`
+	Depth     int                 `bson:"depth" json:"depth"`
+	Body      string              `bson:"body" json:"body"`
+	Replies   int                 `bson:"replies" json:"replies"`
+	CreatedAt time.Time           `bson:"createdAt" json:"createdAt"`
+	EditedAt  *time.Time          `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
+
+	// Hidden is set by the artwork owner; hidden comments are only listed
+	// for the owner.
+	Hidden bool `bson:"hidden,omitempty" json:"hidden,omitempty"`
+	// A deleted comment that still has replies stays behind with its body
+	// cleared so the thread below it survives.
+	Deleted bool `bson:"deleted,omitempty" json:"deleted,omitempty"`
+}
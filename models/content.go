@@ -0,0 +1,52 @@
+package models
+
+// Content ratings, from least to most restricted. Artworks without a rating
+// are general.
+const (
+	RatingGeneral  = "general"
+	RatingMature   = "mature"
+	RatingExplicit = "explicit"
+)
+
+func IsValidContentRating(r string) bool {
+	switch r {
+	case RatingGeneral, RatingMature, RatingExplicit:
+		return true
+	}
+	return false
+}
+
+// IsMatureRating reports whether a rating is gated for viewers who haven't
+// opted in.
+func IsMatureRating(r string) bool {
+	return r == RatingMature || r == RatingExplicit
+}
+
+// ContentWarnings are the flags an artist can attach to a piece.
+var ContentWarnings = map[string]string{
+	"nudity":          "Nudity",
+	"sexual-content":  "Sexual content",
+	"violence":        "Violence",
+	"gore":            "Gore",
+	"horror":          "Horror",
+	"self-harm":       "Self-harm",
+	"drugs":           "Drug use",
+	"flashing-lights": "Flashing lights",
+	"phobia":          "Phobia triggers",
+}
+
+// Viewer preferences for mature content. Hide drops gated artworks from
+// listings; blur includes them behind a blurred preview.
+const (
+	MatureHide = "hide"
+	MatureBlur = "blur"
+	MatureShow = "show"
+)
+
+func IsValidMaturePreference(p string) bool {
+	switch p {
+	case MatureHide, MatureBlur, MatureShow:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxFollowing caps how many artists one user can follow, which bounds the
+// work of building their feed.
+const MaxFollowing = 5000
+
+// Follow is one edge of the social graph: FollowerID follows FollowingID.
+type Follow struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	FollowerID  primitive.ObjectID `bson:"followerId" json:"followerId"`
+	FollowingID primitive.ObjectID `bson:"followingId" json:"followingId"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
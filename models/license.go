@@ -0,0 +1,54 @@
+package models
+
+import "encoding/json"
+
+const (
+	LicenseAllRightsReserved = "all-rights-reserved"
+	LicenseCustom            = "custom"
+)
+
+// LicenseType describes one of the licenses an artist can choose.
+type LicenseType struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+var LicenseTypes = map[string]LicenseType{
+	LicenseAllRightsReserved: {Name: "All Rights Reserved"},
+	"cc-by-4.0":              {Name: "CC BY 4.0", URL: "https://creativecommons.org/licenses/by/4.0/"},
+	"cc-by-sa-4.0":           {Name: "CC BY-SA 4.0", URL: "https://creativecommons.org/licenses/by-sa/4.0/"},
+	"cc-by-nd-4.0":           {Name: "CC BY-ND 4.0", URL: "https://creativecommons.org/licenses/by-nd/4.0/"},
+	"cc-by-nc-4.0":           {Name: "CC BY-NC 4.0", URL: "https://creativecommons.org/licenses/by-nc/4.0/"},
+	"cc-by-nc-sa-4.0":        {Name: "CC BY-NC-SA 4.0", URL: "https://creativecommons.org/licenses/by-nc-sa/4.0/"},
+	"cc-by-nc-nd-4.0":        {Name: "CC BY-NC-ND 4.0", URL: "https://creativecommons.org/licenses/by-nc-nd/4.0/"},
+	"cc0-1.0":                {Name: "CC0 1.0", URL: "https://creativecommons.org/publicdomain/zero/1.0/"},
+	LicenseCustom:            {Name: "Custom license"},
+}
+
+func IsValidLicenseType(t string) bool {
+	_, ok := LicenseTypes[t]
+	return ok
+}
+
+// License is the reuse terms and copyright notice of an artwork. CustomText
+// holds the terms when Type is custom; Attribution says how reusers should
+// credit the artist.
+type License struct {
+	Type            string `bson:"type" json:"type"`
+	CustomText      string `bson:"customText,omitempty" json:"customText,omitempty"`
+	CopyrightHolder string `bson:"copyrightHolder,omitempty" json:"copyrightHolder,omitempty"`
+	CopyrightYear   int    `bson:"copyrightYear,omitempty" json:"copyrightYear,omitempty"`
+	Attribution     string `bson:"attribution,omitempty" json:"attribution,omitempty"`
+}
+
+// MarshalJSON adds the license's display name and URL so clients don't
+// need their own copy of LicenseTypes.
+func (l License) MarshalJSON() ([]byte, error) {
+	type plain License
+	info := LicenseTypes[l.Type]
+	return json.Marshal(struct {
+		plain
+		Name string `json:"name,omitempty"`
+		URL  string `json:"url,omitempty"`
+	}{plain(l), info.Name, info.URL})
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Like records that UserID liked ArtworkID. Artwork.Likes counts these.
+type Like struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ArtworkID primitive.ObjectID `bson:"artworkId" json:"artworkId"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MediaKindMain      = "main"
+	MediaKindProcess   = "process"
+	MediaKindDetail    = "detail"
+	MediaKindAlternate = "alternate"
+)
+
+// MaxMediaPerArtwork caps how many items one artwork can hold.
+const MaxMediaPerArtwork = 20
+
+// MediaItem is one stored file of an artwork: the finished piece, a WIP
+// stage, a detail crop or an alternate view.
+type MediaItem struct {
+	ID               primitive.ObjectID `bson:"_id" json:"id"`
+	Kind             string             `bson:"kind" json:"kind"`
+	Caption          string             `bson:"caption,omitempty" json:"caption,omitempty"`
+	MediaType        string             `bson:"mediaType" json:"mediaType"`
+	URL              string             `bson:"url" json:"url"`
+	PublicID         string             `bson:"publicId" json:"publicId"`
+	Width            int                `bson:"width,omitempty" json:"width,omitempty"`
+	Height           int                `bson:"height,omitempty" json:"height,omitempty"`
+	Duration         float64            `bson:"duration,omitempty" json:"duration,omitempty"`
+	PosterURL        string             `bson:"posterUrl,omitempty" json:"posterUrl,omitempty"`
+	Watermarked      bool               `bson:"watermarked,omitempty" json:"watermarked,omitempty"`
+	OriginalURL      string             `bson:"originalUrl,omitempty" json:"-"`
+	OriginalPublicID string             `bson:"originalPublicId,omitempty" json:"-"`
+	BlurredURL       string             `bson:"blurredUrl,omitempty" json:"blurredUrl,omitempty"`
+	BlurredPublicID  string             `bson:"blurredPublicId,omitempty" json:"-"`
+	CreatedAt        time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FlagTypeNearDuplicate = "near_duplicate"
+
+	FlagStatusOpen      = "open"
+	FlagStatusDismissed = "dismissed"
+	FlagStatusConfirmed = "confirmed"
+)
+
+// ModerationFlag is raised for moderators to review, e.g. when an upload
+// near-duplicates another artist's public artwork.
+type ModerationFlag struct {
+	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Type             string              `bson:"type" json:"type"`
+	Status           string              `bson:"status" json:"status"`
+	ArtworkID        primitive.ObjectID  `bson:"artworkId" json:"artworkId"`
+	UserID           primitive.ObjectID  `bson:"userId" json:"userId"`
+	MatchedArtworkID primitive.ObjectID  `bson:"matchedArtworkId" json:"matchedArtworkId"`
+	MatchedUserID    primitive.ObjectID  `bson:"matchedUserId" json:"matchedUserId"`
+	Distance         int                 `bson:"distance" json:"distance"`
+	ReviewedBy       *primitive.ObjectID `bson:"reviewedBy,omitempty" json:"reviewedBy,omitempty"`
+	ReviewedAt       *time.Time          `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
+	CreatedAt        time.Time           `bson:"createdAt" json:"createdAt"`
+}
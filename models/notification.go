@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification types. Each can be muted in the user's preferences.
+const (
+	NotificationFollow    = "follow"
+	NotificationComment   = "comment"
+	NotificationReply     = "reply"
+	NotificationMilestone = "milestone"
+)
+
+var NotificationTypes = []string{
+	NotificationFollow,
+	NotificationComment,
+	NotificationReply,
+	NotificationMilestone,
+}
+
+func IsValidNotificationType(t string) bool {
+	for _, v := range NotificationTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ViewMilestones are the view counts an artist is told their artwork
+// reached.
+var ViewMilestones = []int{100, 1000, 10000, 100000, 1000000}
+
+// Notification tells UserID that something happened to them or their work.
+// ActorID is whoever caused it; milestones have none.
+type Notification struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID  `bson:"userId" json:"-"`
+	Type      string              `bson:"type" json:"type"`
+	ActorID   *primitive.ObjectID `bson:"actorId,omitempty" json:"actorId,omitempty"`
+	ArtworkID *primitive.ObjectID `bson:"artworkId,omitempty" json:"artworkId,omitempty"`
+	CommentID *primitive.ObjectID `bson:"commentId,omitempty" json:"commentId,omitempty"`
+	Read      bool                `bson:"read" json:"read"`
+	CreatedAt time.Time           `bson:"createdAt" json:"createdAt"`
+
+	// ArtworkTitle is the title when the notification was created, so the
+	// list reads without loading every artwork.
+	ArtworkTitle string `bson:"artworkTitle,omitempty" json:"artworkTitle,omitempty"`
+	Milestone    int    `bson:"milestone,omitempty" json:"milestone,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RelatedNeighbor struct {
+	ArtworkID primitive.ObjectID `bson:"artworkId" json:"artworkId"`
+	Score     float64            `bson:"score" json:"score"`
+}
+
+// RelatedArtworks is the precomputed "more like this" list of one artwork,
+// best match first.
+type RelatedArtworks struct {
+	ArtworkID  primitive.ObjectID `bson:"_id" json:"artworkId"`
+	Neighbors  []RelatedNeighbor  `bson:"neighbors" json:"neighbors"`
+	ComputedAt time.Time          `bson:"computedAt" json:"computedAt"`
+}
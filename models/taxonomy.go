@@ -0,0 +1,56 @@
+package models
+
+// Mediums and Categories are the controlled vocabularies for an artwork's
+// medium and category. Keys are stored; values are display labels.
+var Mediums = map[string]string{
+	"oil":         "Oil",
+	"acrylic":     "Acrylic",
+	"watercolor":  "Watercolor",
+	"gouache":     "Gouache",
+	"ink":         "Ink",
+	"pencil":      "Pencil",
+	"charcoal":    "Charcoal",
+	"pastel":      "Pastel",
+	"mixed-media": "Mixed media",
+	"digital":     "Digital painting",
+	"vector":      "Vector",
+	"pixel-art":   "Pixel art",
+	"3d":          "3D",
+	"photography": "Photography",
+	"printmaking": "Printmaking",
+	"sculpture":   "Sculpture",
+	"textile":     "Textile",
+	"ceramics":    "Ceramics",
+	"animation":   "Animation",
+	"other":       "Other",
+}
+
+var Categories = map[string]string{
+	"illustration":     "Illustration",
+	"character-design": "Character design",
+	"concept-art":      "Concept art",
+	"fan-art":          "Fan art",
+	"portrait":         "Portrait",
+	"landscape":        "Landscape",
+	"still-life":       "Still life",
+	"abstract":         "Abstract",
+	"comics":           "Comics",
+	"storyboard":       "Storyboard",
+	"graphic-design":   "Graphic design",
+	"typography":       "Typography",
+	"architecture":     "Architecture",
+	"fashion":          "Fashion",
+	"game-art":         "Game art",
+	"study":            "Study",
+	"other":            "Other",
+}
+
+func IsValidMedium(m string) bool {
+	_, ok := Mediums[m]
+	return ok
+}
+
+func IsValidCategory(c string) bool {
+	_, ok := Categories[c]
+	return ok
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TrendingArtwork is one row of the materialized explore feed. Every refresh
+// writes a new Generation of rows; readers only see the generation that
+// TrendingState points at. Rank is the position after diversity re-ranking;
+// the filterable artwork fields are copied so the feed can be filtered
+// without a join.
+type TrendingArtwork struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	Generation    primitive.ObjectID `bson:"generation" json:"-"`
+	ArtworkID     primitive.ObjectID `bson:"artworkId" json:"artworkId"`
+	UserID        primitive.ObjectID `bson:"userId" json:"userId"`
+	Rank          int                `bson:"rank" json:"rank"`
+	Score         float64            `bson:"score" json:"score"`
+	Tags          []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	Medium        string             `bson:"medium,omitempty" json:"medium,omitempty"`
+	Category      string             `bson:"category,omitempty" json:"category,omitempty"`
+	ContentRating string             `bson:"contentRating,omitempty" json:"contentRating,omitempty"`
+	ComputedAt    time.Time          `bson:"computedAt" json:"computedAt"`
+}
+
+// TrendingStateID is the _id of the single TrendingState document.
+const TrendingStateID = "current"
+
+// TrendingState records which generation of trending_artworks is live.
+type TrendingState struct {
+	ID         string             `bson:"_id" json:"-"`
+	Generation primitive.ObjectID `bson:"generation" json:"generation"`
+	ComputedAt time.Time          `bson:"computedAt" json:"computedAt"`
+}
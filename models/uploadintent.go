@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	UploadIntentPending   = "pending"
+	UploadIntentCompleted = "completed"
+)
+
+// UploadIntent reserves a storage location that the client uploads to
+// directly. The artwork is only created once the stored object has been
+// verified against the intent's constraints.
+type UploadIntent struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID  `bson:"userId" json:"userId"`
+	Title        string              `bson:"title" json:"title"`
+	AltText      string              `bson:"altText,omitempty" json:"altText,omitempty"`
+	PublicID     string              `bson:"publicId" json:"publicId"`
+	DeliveryType string              `bson:"deliveryType" json:"deliveryType"`
+	ContentType  string              `bson:"contentType" json:"contentType"`
+	MaxBytes     int64               `bson:"maxBytes" json:"maxBytes"`
+	Status       string              `bson:"status" json:"status"`
+	ArtworkID    *primitive.ObjectID `bson:"artworkId,omitempty" json:"artworkId,omitempty"`
+	ExpiresAt    time.Time           `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
+
+	// Visibility and PublishAt are applied to the artwork on completion.
+	Visibility string     `bson:"visibility,omitempty" json:"visibility,omitempty"`
+	PublishAt  *time.Time `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	JobQueued     = "queued"
+	JobProcessing = "processing"
+	JobCompleted  = "completed"
+	JobFailed     = "failed"
+
+	JobItemPending    = "pending"
+	JobItemProcessing = "processing"
+	JobItemSucceeded  = "succeeded"
+	JobItemFailed     = "failed"
+)
+
+// UploadJob tracks a bulk upload from a ZIP archive. Each archive entry is
+// one item with its own status so clients can show per-file progress.
+type UploadJob struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"userId" json:"userId"`
+	Filename   string             `bson:"filename" json:"filename"`
+	Status     string             `bson:"status" json:"status"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	Total      int                `bson:"total" json:"total"`
+	Processed  int                `bson:"processed" json:"processed"`
+	Succeeded  int                `bson:"succeeded" json:"succeeded"`
+	Failed     int                `bson:"failed" json:"failed"`
+	Items      []UploadJobItem    `bson:"items" json:"items"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+	FinishedAt *time.Time         `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
+
+type UploadJobItem struct {
+	Filename    string              `bson:"filename" json:"filename"`
+	Title       string              `bson:"title" json:"title"`
+	Tags        []string            `bson:"tags,omitempty" json:"tags,omitempty"`
+	Visibility  string              `bson:"visibility" json:"visibility"`
+	PublishAt   *time.Time          `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+	Medium      string              `bson:"medium,omitempty" json:"medium,omitempty"`
+	Category    string              `bson:"category,omitempty" json:"category,omitempty"`
+	Description string              `bson:"description,omitempty" json:"description,omitempty"`
+	AltText     string              `bson:"altText,omitempty" json:"altText,omitempty"`
+	License     *License            `bson:"license,omitempty" json:"license,omitempty"`
+	Status      string              `bson:"status" json:"status"`
+	Error       string              `bson:"error,omitempty" json:"error,omitempty"`
+	ArtworkID   *primitive.ObjectID `bson:"artworkId,omitempty" json:"artworkId,omitempty"`
+
+	ContentRating   string   `bson:"contentRating,omitempty" json:"contentRating,omitempty"`
+	ContentWarnings []string `bson:"contentWarnings,omitempty" json:"contentWarnings,omitempty"`
+}
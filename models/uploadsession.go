@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	UploadSessionActive    = "active"
+	UploadSessionCompleted = "completed"
+)
+
+// UploadSession tracks a resumable upload. Received bytes are staged on local
+// disk until the client commits the session, which creates the Artwork.
+type UploadSession struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"userId" json:"userId"`
+	Title       string              `bson:"title" json:"title"`
+	AltText     string              `bson:"altText,omitempty" json:"altText,omitempty"`
+	Filename    string              `bson:"filename,omitempty" json:"filename,omitempty"`
+	ContentType string              `bson:"contentType,omitempty" json:"contentType,omitempty"`
+	Length      int64               `bson:"length" json:"length"`
+	Offset      int64               `bson:"offset" json:"offset"`
+	Status      string              `bson:"status" json:"status"`
+	ArtworkID   *primitive.ObjectID `bson:"artworkId,omitempty" json:"artworkId,omitempty"`
+	ExpiresAt   time.Time           `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time           `bson:"updatedAt" json:"updatedAt"`
+
+	// Visibility and PublishAt are applied to the artwork on commit.
+	Visibility string     `bson:"visibility,omitempty" json:"visibility,omitempty"`
+	PublishAt  *time.Time `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+}
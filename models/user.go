@@ -6,13 +6,28 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const MaxPinnedArtworks = 6
+
 type User struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	FullName       string             `bson:"full_name" json:"fullName"`
-	Email          string             `bson:"email" json:"email"`
-	Password       string             `bson:"password,omitempty" json:"-"`
-	Role           string             `bson:"role" json:"role"`
-	PortfolioViews int                `bson:"portfolioViews" json:"portfolioViews"`
-	CreatedAt      time.Time          `bson:"created_at" json:"createdAt"`
-	UpdatedAt      time.Time          `bson:"updated_at" json:"updatedAt"`
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	FullName       string               `bson:"full_name" json:"fullName"`
+	Email          string               `bson:"email" json:"email"`
+	Password       string               `bson:"password,omitempty" json:"-"`
+	Role           string               `bson:"role" json:"role"`
+	PortfolioViews int                  `bson:"portfolioViews" json:"portfolioViews"`
+	FollowersCount int                  `bson:"followers_count" json:"followersCount"`
+	FollowingCount int                  `bson:"following_count" json:"followingCount"`
+	Watermark      *WatermarkSettings   `bson:"watermark,omitempty" json:"watermark,omitempty"`
+	PinnedArtworks []primitive.ObjectID `bson:"pinned_artworks,omitempty" json:"pinnedArtworks,omitempty"`
+	DefaultLicense *License             `bson:"default_license,omitempty" json:"defaultLicense,omitempty"`
+	MatureContent  string               `bson:"mature_content,omitempty" json:"matureContent,omitempty"`
+	CreatedAt      time.Time            `bson:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time            `bson:"updated_at" json:"updatedAt"`
+
+	// MutedNotifications lists the notification types the user opted out of.
+	MutedNotifications []string `bson:"muted_notifications,omitempty" json:"-"`
+
+	// SearchWords are the lowercased words of FullName, matched by name
+	// suggestions.
+	SearchWords []string `bson:"search_words" json:"-"`
 }
@@ -0,0 +1,43 @@
+package models
+
+const (
+	WatermarkText = "text"
+	WatermarkLogo = "logo"
+)
+
+// WatermarkSettings control the overlay applied to the public variant of a
+// user's uploads. Opacity is 1-100 and Scale is the overlay width as a
+// fraction of the image width.
+type WatermarkSettings struct {
+	Enabled      bool    `bson:"enabled" json:"enabled"`
+	Type         string  `bson:"type" json:"type"`
+	Text         string  `bson:"text,omitempty" json:"text,omitempty"`
+	LogoPublicID string  `bson:"logoPublicId,omitempty" json:"logoPublicId,omitempty"`
+	LogoURL      string  `bson:"logoUrl,omitempty" json:"logoUrl,omitempty"`
+	Position     string  `bson:"position" json:"position"`
+	Opacity      int     `bson:"opacity" json:"opacity"`
+	Scale        float64 `bson:"scale" json:"scale"`
+}
+
+// WatermarkPositions maps accepted positions to Cloudinary gravities.
+var WatermarkPositions = map[string]string{
+	"top-left":     "north_west",
+	"top":          "north",
+	"top-right":    "north_east",
+	"left":         "west",
+	"center":       "center",
+	"right":        "east",
+	"bottom-left":  "south_west",
+	"bottom":       "south",
+	"bottom-right": "south_east",
+}
+
+func DefaultWatermarkSettings() WatermarkSettings {
+	return WatermarkSettings{
+		Enabled:  false,
+		Type:     WatermarkText,
+		Position: "bottom-right",
+		Opacity:  50,
+		Scale:    0.25,
+	}
+}
@@ -15,24 +15,116 @@ func ArtworkRoutes(router *gin.Engine) {
 		"/upload",
 		middleware.Authenticate(),
 		middleware.RateLimiter(0.2, 1), 
-		middleware.UploadMiddleware(10, []string{"image/"}),
+		middleware.MediaUploadMiddleware(middleware.ArtworkMediaLimits),
 		controllers.UploadArtwork,
 	)
 
 	
+	artworks.POST(
+		"/import-url",
+		middleware.Authenticate(),
+		middleware.RateLimiter(0.2, 1),
+		controllers.ImportArtworkFromURL,
+	)
+
+	artworks.POST(
+		"/upload-intent",
+		middleware.Authenticate(),
+		middleware.RateLimiter(0.2, 1),
+		controllers.CreateUploadIntent,
+	)
+
+	artworks.POST(
+		"/upload-complete",
+		middleware.Authenticate(),
+		middleware.RateLimiter(0.2, 1),
+		controllers.CompleteUpload,
+	)
+
+	bulk := artworks.Group("/bulk", middleware.Authenticate())
+	{
+		bulk.POST(
+			"",
+			middleware.RateLimiter(0.05, 1),
+			middleware.UploadMiddleware(middleware.BulkMaxArchiveMB, middleware.BulkArchiveTypes),
+			controllers.UploadBulkArtworks,
+		)
+		bulk.GET("", middleware.RateLimiter(1, 5), controllers.GetMyUploadJobs)
+		bulk.GET("/:id", middleware.RateLimiter(2, 10), controllers.GetUploadJob)
+	}
+
+	uploads := artworks.Group("/uploads", middleware.Authenticate())
+	{
+		uploads.POST("", middleware.RateLimiter(0.2, 1), controllers.CreateUploadSession)
+		uploads.HEAD("/:id", middleware.RateLimiter(2, 10), controllers.GetUploadOffset)
+		uploads.PATCH("/:id", middleware.RateLimiter(5, 20), controllers.PatchUpload)
+		uploads.POST("/:id/commit", middleware.RateLimiter(0.2, 1), controllers.CommitUpload)
+		uploads.DELETE("/:id", middleware.RateLimiter(1, 3), controllers.CancelUpload)
+	}
+
+	artworks.GET(
+		"/taxonomy",
+		middleware.RateLimiter(2, 5),
+		controllers.GetTaxonomy,
+	)
+
 	artworks.GET(
 		"/public",
+		middleware.OptionalAuthenticate(),
 		middleware.RateLimiter(2, 5),
 		controllers.GetPublicArtworks,
 	)
 
+	artworks.GET(
+		"/explore",
+		middleware.OptionalAuthenticate(),
+		middleware.RateLimiter(2, 5),
+		controllers.GetExploreArtworks,
+	)
+
 	
 	artworks.GET(
 		"/:id",
+		middleware.OptionalAuthenticate(),
 		middleware.RateLimiter(1, 5),
 		controllers.GetArtworkAndCountView,
 	)
 
+	artworks.GET(
+		"/:id/related",
+		middleware.OptionalAuthenticate(),
+		middleware.RateLimiter(2, 5),
+		controllers.GetRelatedArtworks,
+	)
+
+	artworks.POST(
+		"/:id/like",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.LikeArtwork,
+	)
+
+	artworks.DELETE(
+		"/:id/like",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.UnlikeArtwork,
+	)
+
+	artworks.GET(
+		"/:id/comments",
+		middleware.OptionalAuthenticate(),
+		middleware.RateLimiter(2, 5),
+		controllers.GetArtworkComments,
+	)
+
+	artworks.POST(
+		"/:id/comments",
+		middleware.Authenticate(),
+		middleware.RateLimiterBy(0.2, 3, middleware.UserKey),
+		controllers.CreateComment,
+	)
+
 	
 	artworks.GET(
 		"/mine",
@@ -41,6 +133,77 @@ func ArtworkRoutes(router *gin.Engine) {
 		controllers.GetMyArtworks,
 	)
 
+	artworks.PUT(
+		"/order",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.ReorderArtworks,
+	)
+
+	artworks.PUT(
+		"/pinned",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.SetPinnedArtworks,
+	)
+
+	artworks.GET(
+		"/missing-alt-text",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.GetMissingAltText,
+	)
+
+	artworks.GET(
+		"/trash",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.GetTrash,
+	)
+
+	artworks.POST(
+		"/:id/restore",
+		middleware.Authenticate(),
+		middleware.RateLimiter(0.3, 1),
+		controllers.RestoreArtwork,
+	)
+
+	artworks.DELETE(
+		"/trash/:id",
+		middleware.Authenticate(),
+		middleware.RateLimiter(0.3, 1),
+		controllers.PurgeArtwork,
+	)
+
+	artworks.PATCH(
+		"/:id",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.UpdateArtwork,
+	)
+
+	artworks.POST(
+		"/:id/media",
+		middleware.Authenticate(),
+		middleware.RateLimiter(0.2, 1),
+		middleware.MediaUploadMiddleware(middleware.ArtworkMediaLimits),
+		controllers.AddArtworkMedia,
+	)
+
+	artworks.PUT(
+		"/:id/media/order",
+		middleware.Authenticate(),
+		middleware.RateLimiter(1, 3),
+		controllers.ReorderArtworkMedia,
+	)
+
+	artworks.DELETE(
+		"/:id/media/:mediaId",
+		middleware.Authenticate(),
+		middleware.RateLimiter(0.3, 1),
+		controllers.RemoveArtworkMedia,
+	)
+
 	artworks.DELETE(
 		"/:id",
 		middleware.Authenticate(),
@@ -51,11 +214,23 @@ func ArtworkRoutes(router *gin.Engine) {
 
 func PublicPortfolioRoutes(router *gin.Engine) {
 
-	portfolio := router.Group("/portfolio")
+	portfolio := router.Group("/portfolio", middleware.OptionalAuthenticate())
 
 	portfolio.GET(
 		"/:name",
 		middleware.RateLimiter(1, 5),
 		controllers.GetPublicPortfolioByName,
 	)
+
+	portfolio.GET(
+		"/:name/collections",
+		middleware.RateLimiter(1, 5),
+		controllers.GetPublicCollections,
+	)
+
+	portfolio.GET(
+		"/:name/collections/:slug",
+		middleware.RateLimiter(1, 5),
+		controllers.GetPublicCollection,
+	)
 }
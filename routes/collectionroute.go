@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/controllers"
+	"github.com/nerokome/artfolio-backend/middleware"
+)
+
+func CollectionRoutes(router *gin.Engine) {
+	collections := router.Group("/collections", middleware.Authenticate())
+	{
+		collections.POST("", middleware.RateLimiter(0.5, 2), controllers.CreateCollection)
+		collections.GET("", middleware.RateLimiter(1, 5), controllers.GetMyCollections)
+		collections.GET("/:id", middleware.RateLimiter(1, 5), controllers.GetMyCollection)
+		collections.PATCH("/:id", middleware.RateLimiter(1, 3), controllers.UpdateCollection)
+		collections.PUT("/:id/artworks", middleware.RateLimiter(1, 3), controllers.SetCollectionArtworks)
+		collections.POST("/:id/artworks", middleware.RateLimiter(1, 5), controllers.AddCollectionArtwork)
+		collections.DELETE("/:id/artworks/:artworkId", middleware.RateLimiter(1, 5), controllers.RemoveCollectionArtwork)
+		collections.DELETE("/:id", middleware.RateLimiter(0.3, 1), controllers.DeleteCollection)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/controllers"
+	"github.com/nerokome/artfolio-backend/middleware"
+)
+
+func CommentRoutes(router *gin.Engine) {
+	comments := router.Group("/comments", middleware.Authenticate())
+	{
+		comments.PATCH("/:id", middleware.RateLimiter(0.5, 3), controllers.UpdateComment)
+		comments.DELETE("/:id", middleware.RateLimiter(1, 3), controllers.DeleteComment)
+		comments.PUT("/:id/hidden", middleware.RateLimiter(1, 3), controllers.SetCommentHidden)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/controllers"
+	"github.com/nerokome/artfolio-backend/middleware"
+)
+
+func ModerationRoutes(router *gin.Engine) {
+	moderation := router.Group("/moderation")
+
+	moderation.Use(middleware.Authenticate(), middleware.RequireRole("admin", "moderator"))
+	{
+		moderation.GET("/flags", controllers.GetModerationFlags)
+		moderation.PATCH("/flags/:id", controllers.ResolveModerationFlag)
+		moderation.GET("/similar", controllers.FindSimilarArtworks)
+	}
+}
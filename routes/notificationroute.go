@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/controllers"
+	"github.com/nerokome/artfolio-backend/middleware"
+)
+
+func NotificationRoutes(router *gin.Engine) {
+	notifications := router.Group("/notifications", middleware.Authenticate())
+	{
+		notifications.GET("", middleware.RateLimiter(2, 5), controllers.GetNotifications)
+		notifications.POST("/read-all", middleware.RateLimiter(1, 3), controllers.MarkAllNotificationsRead)
+		notifications.PATCH("/:id", middleware.RateLimiter(2, 5), controllers.UpdateNotification)
+	}
+}
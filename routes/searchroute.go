@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/controllers"
+	"github.com/nerokome/artfolio-backend/middleware"
+)
+
+func SearchRoutes(router *gin.Engine) {
+	search := router.Group("/search", middleware.OptionalAuthenticate())
+
+	search.GET(
+		"",
+		middleware.RateLimiter(2, 5),
+		controllers.Search,
+	)
+	search.GET(
+		"/suggest",
+		middleware.RateLimiter(5, 10),
+		controllers.SearchSuggestions,
+	)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/controllers"
+	"github.com/nerokome/artfolio-backend/middleware"
+)
+
+func TagRoutes(router *gin.Engine) {
+	router.GET(
+		"/tags",
+		middleware.RateLimiter(2, 10),
+		controllers.GetTags,
+	)
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nerokome/artfolio-backend/controllers"
+	"github.com/nerokome/artfolio-backend/middleware"
+)
+
+func UserRoutes(router *gin.Engine) {
+	me := router.Group("/users/me")
+
+	me.Use(middleware.Authenticate(), middleware.RateLimiter(1, 3))
+	{
+		me.GET("/watermark", controllers.GetWatermarkSettings)
+		me.PUT("/watermark", controllers.UpdateWatermarkSettings)
+		me.GET("/license", controllers.GetDefaultLicense)
+		me.PUT("/license", controllers.UpdateDefaultLicense)
+		me.GET("/content-preferences", controllers.GetContentPreferences)
+		me.PUT("/content-preferences", controllers.UpdateContentPreferences)
+		me.GET("/notification-preferences", controllers.GetNotificationPreferences)
+		me.PUT("/notification-preferences", controllers.UpdateNotificationPreferences)
+		me.POST(
+			"/watermark/logo",
+			middleware.UploadMiddleware(2, []string{"image/png"}),
+			controllers.UploadWatermarkLogo,
+		)
+	}
+
+	users := router.Group("/users")
+	{
+		users.GET("/:id/followers", middleware.RateLimiter(2, 5), controllers.GetFollowers)
+		users.GET("/:id/following", middleware.RateLimiter(2, 5), controllers.GetFollowing)
+		users.GET("/:id/likes", middleware.OptionalAuthenticate(), middleware.RateLimiter(2, 5), controllers.GetLikedArtworks)
+		users.POST("/:id/follow", middleware.Authenticate(), middleware.RateLimiter(1, 3), controllers.FollowUser)
+		users.DELETE("/:id/follow", middleware.Authenticate(), middleware.RateLimiter(1, 3), controllers.UnfollowUser)
+	}
+}
+
+func FeedRoutes(router *gin.Engine) {
+	router.GET(
+		"/feed",
+		middleware.Authenticate(),
+		middleware.RateLimiter(2, 5),
+		controllers.GetFeed,
+	)
+}
@@ -0,0 +1,313 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Field weights: a hit in the title counts three times one in the
+// description.
+const (
+	weightTitle       = 3.0
+	weightTags        = 2.0
+	weightArtist      = 1.5
+	weightDescription = 1.0
+)
+
+// How much a prefix or typo match of a query term is worth next to an exact
+// one. Typo matches are further divided by their edit distance.
+const (
+	prefixFactor    = 0.7
+	maxPrefixTerms  = 50
+	minPrefixLength = 2
+)
+
+// MemoryIndex is an in-process inverted index for local development. It
+// holds a full copy of the documents, so it is rebuilt from the database
+// with Replace rather than updated in place.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[primitive.ObjectID]Document
+	postings map[string]map[primitive.ObjectID]float64
+	vocab    []string
+	counts   map[string]int
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     map[primitive.ObjectID]Document{},
+		postings: map[string]map[primitive.ObjectID]float64{},
+		counts:   map[string]int{},
+	}
+}
+
+func (m *MemoryIndex) Replace(docs []Document) {
+	byID := make(map[primitive.ObjectID]Document, len(docs))
+	postings := map[string]map[primitive.ObjectID]float64{}
+	add := func(id primitive.ObjectID, text string, weight float64) {
+		for _, term := range tokenize(text) {
+			if postings[term] == nil {
+				postings[term] = map[primitive.ObjectID]float64{}
+			}
+			postings[term][id] += weight
+		}
+	}
+
+	for _, doc := range docs {
+		byID[doc.ID] = doc
+		add(doc.ID, doc.Title, weightTitle)
+		add(doc.ID, doc.Handle, weightTitle)
+		add(doc.ID, strings.Join(doc.Tags, " "), weightTags)
+		add(doc.ID, doc.ArtistName, weightArtist)
+		add(doc.ID, doc.Description, weightDescription)
+	}
+
+	vocab := make([]string, 0, len(postings))
+	counts := make(map[string]int, len(postings))
+	for term, posting := range postings {
+		vocab = append(vocab, term)
+		counts[term] = len(posting)
+	}
+	sort.Strings(vocab)
+
+	m.mu.Lock()
+	m.docs, m.postings, m.vocab, m.counts = byID, postings, vocab, counts
+	m.mu.Unlock()
+}
+
+// expand maps a query term to the indexed words it matches and how much
+// each match is worth. Only the last term of a query completes as a prefix,
+// since that is the one still being typed.
+func (m *MemoryIndex) expand(term string, prefix bool) map[string]float64 {
+	matches := map[string]float64{}
+	if _, ok := m.postings[term]; ok {
+		matches[term] = 1
+	}
+
+	if prefix && len(term) >= minPrefixLength {
+		for i := sort.SearchStrings(m.vocab, term); i < len(m.vocab) && len(matches) < maxPrefixTerms; i++ {
+			word := m.vocab[i]
+			if !strings.HasPrefix(word, term) {
+				break
+			}
+			if word != term {
+				matches[word] = prefixFactor
+			}
+		}
+	}
+
+	if limit := maxEdits(term); limit > 0 {
+		for _, word := range m.vocab {
+			d := editDistance(term, word, limit)
+			if d == 0 || d > limit {
+				continue
+			}
+			if factor := 1 / float64(1+d); factor > matches[word] {
+				matches[word] = factor
+			}
+		}
+	}
+	return matches
+}
+
+func matchesQuery(doc Document, q Query) bool {
+	if q.Kind != "" && doc.Kind != q.Kind {
+		return false
+	}
+	if doc.Kind != KindArtwork {
+		return true
+	}
+	if q.Medium != "" && doc.Medium != q.Medium {
+		return false
+	}
+	if q.Category != "" && doc.Category != q.Category {
+		return false
+	}
+	if q.HideMature && models.IsMatureRating(doc.ContentRating) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		found := false
+		for _, t := range doc.Tags {
+			found = found || t == tag
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Search scores documents by the sum, over query terms, of the best match
+// for each term weighted by field and inverse document frequency.
+func (m *MemoryIndex) Search(ctx context.Context, q Query) (Results, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := Results{Artworks: []Hit{}, Artists: []Hit{}, Facets: newFacets()}
+	terms := tokenize(q.Text)
+	total := float64(len(m.docs))
+
+	scores := map[primitive.ObjectID]float64{}
+	for i, term := range terms {
+		best := map[primitive.ObjectID]float64{}
+		for word, factor := range m.expand(term, i == len(terms)-1) {
+			idf := math.Log(1 + total/float64(len(m.postings[word])))
+			for id, tf := range m.postings[word] {
+				if s := factor * idf * tf; s > best[id] {
+					best[id] = s
+				}
+			}
+		}
+		for id, s := range best {
+			scores[id] += s
+		}
+	}
+
+	// A last term that completes to indexed words is still being typed, not
+	// misspelt.
+	completes := func(i int, term string) bool {
+		if i != len(terms)-1 || len(term) < minPrefixLength {
+			return false
+		}
+		j := sort.SearchStrings(m.vocab, term)
+		return j < len(m.vocab) && strings.HasPrefix(m.vocab[j], term)
+	}
+	if corrected, changed := correct(terms, m.counts, completes); changed {
+		results.Corrected = strings.Join(corrected, " ")
+	}
+
+	for id, score := range scores {
+		doc := m.docs[id]
+		if !matchesQuery(doc, q) {
+			continue
+		}
+		hit := Hit{ID: id, Score: score}
+		if doc.Kind == KindArtist {
+			results.Artists = append(results.Artists, hit)
+			continue
+		}
+		results.Artworks = append(results.Artworks, hit)
+		if doc.Medium != "" {
+			results.Facets.Mediums[doc.Medium]++
+		}
+		if doc.Category != "" {
+			results.Facets.Categories[doc.Category]++
+		}
+		for _, t := range doc.Tags {
+			results.Facets.Tags[t]++
+		}
+	}
+	results.Facets.Tags = topCounts(results.Facets.Tags, maxFacetTags)
+
+	results.TotalArtworks, results.TotalArtists = len(results.Artworks), len(results.Artists)
+	results.Artworks = m.page(results.Artworks, q)
+	results.Artists = m.page(results.Artists, q)
+	return results, nil
+}
+
+// page sorts hits by score, newest first on ties, and applies the query's
+// offset and limit.
+func (m *MemoryIndex) page(hits []Hit, q Query) []Hit {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return m.docs[hits[i].ID].CreatedAt.After(m.docs[hits[j].ID].CreatedAt)
+	})
+	if q.Offset >= len(hits) {
+		return []Hit{}
+	}
+	hits = hits[q.Offset:]
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits
+}
+
+// Suggest completes prefix to tags, most used first, then artist names and
+// artwork titles that have a word starting with it.
+func (m *MemoryIndex) Suggest(ctx context.Context, q Query) ([]Suggestion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	prefix := strings.ToLower(strings.TrimSpace(q.Text))
+	suggestions := []Suggestion{}
+	if prefix == "" {
+		return suggestions, nil
+	}
+	tagPrefix := strings.ReplaceAll(prefix, " ", "-")
+
+	tags := map[string]int{}
+	var artists, artworks []Suggestion
+	for id, doc := range m.docs {
+		id := id
+		if doc.Kind == KindArtist {
+			if wordPrefix(doc.Title, prefix) {
+				artists = append(artists, Suggestion{Text: doc.Title, Kind: SuggestArtist, ID: &id})
+			}
+			continue
+		}
+		if q.HideMature && models.IsMatureRating(doc.ContentRating) {
+			continue
+		}
+		for _, t := range doc.Tags {
+			if strings.HasPrefix(t, tagPrefix) {
+				tags[t]++
+			}
+		}
+		if wordPrefix(doc.Title, prefix) {
+			artworks = append(artworks, Suggestion{Text: doc.Title, Kind: SuggestArtwork, ID: &id})
+		}
+	}
+
+	for _, t := range sortedByCount(tags) {
+		suggestions = append(suggestions, Suggestion{Text: t, Kind: SuggestTag})
+	}
+	for _, group := range [][]Suggestion{artists, artworks} {
+		sort.Slice(group, func(i, j int) bool { return group[i].Text < group[j].Text })
+		suggestions = append(suggestions, group...)
+	}
+	if q.Limit > 0 && len(suggestions) > q.Limit {
+		suggestions = suggestions[:q.Limit]
+	}
+	return suggestions, nil
+}
+
+// wordPrefix reports whether text starts with prefix or has a word that
+// does.
+func wordPrefix(text, prefix string) bool {
+	text = strings.ToLower(text)
+	return strings.HasPrefix(text, prefix) || strings.Contains(text, " "+prefix)
+}
+
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func topCounts(counts map[string]int, n int) map[string]int {
+	top := map[string]int{}
+	for i, k := range sortedByCount(counts) {
+		if i == n {
+			break
+		}
+		top[k] = counts[k]
+	}
+	return top
+}
@@ -0,0 +1,315 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	dawnID     = primitive.NewObjectID()
+	harbourID  = primitive.NewObjectID()
+	miraID     = primitive.NewObjectID()
+	nocturneID = primitive.NewObjectID()
+	adaID      = primitive.NewObjectID()
+)
+
+func testIndex() *MemoryIndex {
+	now := time.Now()
+	index := NewMemoryIndex()
+	index.Replace([]Document{
+		{
+			ID:          dawnID,
+			Kind:        KindArtwork,
+			Title:       "Lighthouse at Dawn",
+			Description: "A lighthouse on the cliffs",
+			Tags:        []string{"seascape", "oil-painting"},
+			Medium:      "oil",
+			Category:    "painting",
+			ArtistName:  "Ada Lovelace",
+			CreatedAt:   now.Add(-3 * time.Hour),
+		},
+		{
+			ID:          harbourID,
+			Kind:        KindArtwork,
+			Title:       "Harbour Study",
+			Description: "Boats near a lighthouse",
+			Tags:        []string{"seascape"},
+			Medium:      "watercolor",
+			Category:    "painting",
+			CreatedAt:   now.Add(-2 * time.Hour),
+		},
+		{
+			ID:        miraID,
+			Kind:      KindArtwork,
+			Title:     "Portrait of Mira",
+			Tags:      []string{"portrait", "charcoal"},
+			Medium:    "charcoal",
+			Category:  "drawing",
+			CreatedAt: now.Add(-1 * time.Hour),
+		},
+		{
+			ID:            nocturneID,
+			Kind:          KindArtwork,
+			Title:         "Nocturne Lighthouse",
+			Tags:          []string{"figure"},
+			Medium:        "oil",
+			Category:      "painting",
+			ContentRating: models.RatingMature,
+			CreatedAt:     now,
+		},
+		{
+			ID:        adaID,
+			Kind:      KindArtist,
+			Title:     "Ada Lovelace",
+			Handle:    "ada-lovelace",
+			CreatedAt: now,
+		},
+	})
+	return index
+}
+
+func hitIDs(hits []Hit) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func TestMemoryIndexSearch(t *testing.T) {
+	index := testIndex()
+
+	tests := []struct {
+		name      string
+		query     Query
+		artworks  []primitive.ObjectID
+		artists   []primitive.ObjectID
+		total     int
+		corrected string
+	}{
+		{
+			name:     "title outranks description",
+			query:    Query{Text: "lighthouse"},
+			artworks: []primitive.ObjectID{dawnID, nocturneID, harbourID},
+			total:    3,
+		},
+		{
+			name:     "rare terms weigh more",
+			query:    Query{Text: "harbour lighthouse"},
+			artworks: []primitive.ObjectID{harbourID, dawnID, nocturneID},
+			total:    3,
+		},
+		{
+			name:     "artists and artworks by them",
+			query:    Query{Text: "lovelace"},
+			artworks: []primitive.ObjectID{dawnID},
+			artists:  []primitive.ObjectID{adaID},
+			total:    1,
+		},
+		{
+			name:    "kind narrows to artists",
+			query:   Query{Text: "lovelace", Kind: KindArtist},
+			artists: []primitive.ObjectID{adaID},
+			total:   0,
+		},
+		{
+			name:     "hyphenated tags match their parts",
+			query:    Query{Text: "painting"},
+			artworks: []primitive.ObjectID{dawnID},
+			total:    1,
+		},
+		{
+			name:      "typo is corrected",
+			query:     Query{Text: "lighthose"},
+			artworks:  []primitive.ObjectID{dawnID, nocturneID, harbourID},
+			total:     3,
+			corrected: "lighthouse",
+		},
+		{
+			name:      "typo in an earlier term",
+			query:     Query{Text: "portriat mira"},
+			artworks:  []primitive.ObjectID{miraID},
+			total:     1,
+			corrected: "portrait mira",
+		},
+		{
+			name:     "last term completes as a prefix",
+			query:    Query{Text: "lightho"},
+			artworks: []primitive.ObjectID{dawnID, nocturneID, harbourID},
+			total:    3,
+		},
+		{
+			name:     "only the last term completes",
+			query:    Query{Text: "harb dawn"},
+			artworks: []primitive.ObjectID{dawnID},
+			total:    1,
+		},
+		{
+			name:     "medium filter",
+			query:    Query{Text: "lighthouse", Medium: "oil"},
+			artworks: []primitive.ObjectID{dawnID, nocturneID},
+			total:    2,
+		},
+		{
+			name:     "tag filter",
+			query:    Query{Text: "lighthouse", Tags: []string{"seascape"}},
+			artworks: []primitive.ObjectID{dawnID, harbourID},
+			total:    2,
+		},
+		{
+			name:     "mature work hidden",
+			query:    Query{Text: "lighthouse", HideMature: true},
+			artworks: []primitive.ObjectID{dawnID, harbourID},
+			total:    2,
+		},
+		{
+			name:     "offset and limit",
+			query:    Query{Text: "lighthouse", Offset: 1, Limit: 1},
+			artworks: []primitive.ObjectID{nocturneID},
+			total:    3,
+		},
+		{
+			name:     "offset past the end",
+			query:    Query{Text: "lighthouse", Offset: 5},
+			artworks: []primitive.ObjectID{},
+			total:    3,
+		},
+		{
+			name:     "no match",
+			query:    Query{Text: "xylophone"},
+			artworks: []primitive.ObjectID{},
+			total:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if tt.artworks == nil {
+				tt.artworks = []primitive.ObjectID{}
+			}
+			if tt.artists == nil {
+				tt.artists = []primitive.ObjectID{}
+			}
+			if got := hitIDs(results.Artworks); !reflect.DeepEqual(got, tt.artworks) {
+				t.Errorf("artworks = %v, want %v", got, tt.artworks)
+			}
+			if got := hitIDs(results.Artists); !reflect.DeepEqual(got, tt.artists) {
+				t.Errorf("artists = %v, want %v", got, tt.artists)
+			}
+			if results.TotalArtworks != tt.total {
+				t.Errorf("TotalArtworks = %d, want %d", results.TotalArtworks, tt.total)
+			}
+			if results.Corrected != tt.corrected {
+				t.Errorf("Corrected = %q, want %q", results.Corrected, tt.corrected)
+			}
+		})
+	}
+}
+
+func TestMemoryIndexFacets(t *testing.T) {
+	index := testIndex()
+
+	tests := []struct {
+		name  string
+		query Query
+		want  Facets
+	}{
+		{
+			name:  "all matches",
+			query: Query{Text: "lighthouse"},
+			want: Facets{
+				Mediums:    map[string]int{"oil": 2, "watercolor": 1},
+				Categories: map[string]int{"painting": 3},
+				Tags:       map[string]int{"seascape": 2, "oil-painting": 1, "figure": 1},
+			},
+		},
+		{
+			name:  "counted before paging",
+			query: Query{Text: "lighthouse", Limit: 1},
+			want: Facets{
+				Mediums:    map[string]int{"oil": 2, "watercolor": 1},
+				Categories: map[string]int{"painting": 3},
+				Tags:       map[string]int{"seascape": 2, "oil-painting": 1, "figure": 1},
+			},
+		},
+		{
+			name:  "hidden mature work is not counted",
+			query: Query{Text: "lighthouse", HideMature: true},
+			want: Facets{
+				Mediums:    map[string]int{"oil": 1, "watercolor": 1},
+				Categories: map[string]int{"painting": 2},
+				Tags:       map[string]int{"seascape": 2, "oil-painting": 1},
+			},
+		},
+		{
+			name:  "artists are not counted",
+			query: Query{Text: "ada", Kind: KindArtist},
+			want:  newFacets(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if !reflect.DeepEqual(results.Facets, tt.want) {
+				t.Errorf("Facets = %+v, want %+v", results.Facets, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryIndexSuggest(t *testing.T) {
+	index := testIndex()
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"tags first", Query{Text: "sea"}, []string{"tag:seascape"}},
+		{"artists", Query{Text: "ada"}, []string{"artist:Ada Lovelace"}},
+		{"any word of a title", Query{Text: "li"}, []string{"artwork:Lighthouse at Dawn", "artwork:Nocturne Lighthouse"}},
+		{"mature work included", Query{Text: "noc"}, []string{"artwork:Nocturne Lighthouse"}},
+		{"mature work hidden", Query{Text: "noc", HideMature: true}, []string{}},
+		{"limit", Query{Text: "li", Limit: 1}, []string{"artwork:Lighthouse at Dawn"}},
+		{"blank", Query{Text: "  "}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, err := index.Suggest(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Suggest: %v", err)
+			}
+			got := make([]string, len(suggestions))
+			for i, s := range suggestions {
+				got[i] = s.Kind + ":" + s.Text
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.query.Text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryIndexReplace(t *testing.T) {
+	index := testIndex()
+	index.Replace([]Document{{ID: miraID, Kind: KindArtwork, Title: "Portrait of Mira"}})
+
+	results, err := index.Search(context.Background(), Query{Text: "lighthouse"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if results.TotalArtworks != 0 {
+		t.Errorf("TotalArtworks after Replace = %d, want 0", results.TotalArtworks)
+	}
+}
@@ -0,0 +1,332 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerokome/artfolio-backend/database"
+	"github.com/nerokome/artfolio-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultLimit = 20
+
+// vocabularyTTL is how long the tag vocabulary used for typo correction is
+// reused before it is counted again.
+const vocabularyTTL = 10 * time.Minute
+
+// MongoIndex searches the live artworks and users collections through their
+// text indexes, so it needs no loading. $text has no fuzzy matching: typo
+// tolerance is limited to correcting a query that finds nothing against the
+// words used in tags.
+type MongoIndex struct {
+	artworkFilter bson.M
+
+	mu           sync.Mutex
+	vocabulary   map[string]int
+	vocabularyAt time.Time
+}
+
+// NewMongoIndex returns an index over the artworks matching artworkFilter,
+// which should restrict results to listed, public work.
+func NewMongoIndex(artworkFilter bson.M) *MongoIndex {
+	return &MongoIndex{artworkFilter: artworkFilter}
+}
+
+type facetCount struct {
+	Value string `bson:"_id"`
+	Count int    `bson:"n"`
+}
+
+type scoredID struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Score float64            `bson:"score"`
+}
+
+func (m *MongoIndex) filter(q Query) bson.M {
+	filter := bson.M{}
+	for k, v := range m.artworkFilter {
+		filter[k] = v
+	}
+	if len(q.Tags) > 0 {
+		filter["tags"] = bson.M{"$all": q.Tags}
+	}
+	if q.Medium != "" {
+		filter["medium"] = q.Medium
+	}
+	if q.Category != "" {
+		filter["category"] = q.Category
+	}
+	if q.HideMature {
+		filter["contentRating"] = bson.M{"$nin": []string{models.RatingMature, models.RatingExplicit}}
+	}
+	return filter
+}
+
+func (m *MongoIndex) Search(ctx context.Context, q Query) (Results, error) {
+	results := Results{Artworks: []Hit{}, Artists: []Hit{}, Facets: newFacets()}
+	terms := tokenize(q.Text)
+	if len(terms) == 0 {
+		return results, nil
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+
+	if err := m.search(ctx, q, strings.Join(terms, " "), &results); err != nil {
+		return results, err
+	}
+	if results.TotalArtworks+results.TotalArtists > 0 {
+		return results, nil
+	}
+
+	vocabulary, err := m.tagVocabulary(ctx)
+	if err != nil {
+		return results, err
+	}
+	corrected, changed := correct(terms, vocabulary, func(int, string) bool { return false })
+	if !changed {
+		return results, nil
+	}
+	results.Corrected = strings.Join(corrected, " ")
+	err = m.search(ctx, q, results.Corrected, &results)
+	return results, err
+}
+
+func (m *MongoIndex) search(ctx context.Context, q Query, text string, results *Results) error {
+	if q.Kind != KindArtist {
+		filter := m.filter(q)
+		filter["$text"] = bson.M{"$search": text}
+
+		groupBy := func(field string) []bson.M {
+			return []bson.M{
+				{"$match": bson.M{field: bson.M{"$nin": []interface{}{"", nil}}}},
+				{"$group": bson.M{"_id": "$" + field, "n": bson.M{"$sum": 1}}},
+			}
+		}
+		pipeline := []bson.M{
+			{"$match": filter},
+			{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}},
+			{"$facet": bson.M{
+				"hits": []bson.M{
+					{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "createdAt", Value: -1}}},
+					{"$skip": q.Offset},
+					{"$limit": q.Limit},
+					{"$project": bson.M{"score": 1}},
+				},
+				"total":      []bson.M{{"$count": "n"}},
+				"mediums":    groupBy("medium"),
+				"categories": groupBy("category"),
+				"tags": []bson.M{
+					{"$unwind": "$tags"},
+					{"$group": bson.M{"_id": "$tags", "n": bson.M{"$sum": 1}}},
+					{"$sort": bson.D{{Key: "n", Value: -1}, {Key: "_id", Value: 1}}},
+					{"$limit": maxFacetTags},
+				},
+			}},
+		}
+
+		cursor, err := database.Collection("artworks").Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		var out []struct {
+			Hits       []scoredID   `bson:"hits"`
+			Total      []facetCount `bson:"total"`
+			Mediums    []facetCount `bson:"mediums"`
+			Categories []facetCount `bson:"categories"`
+			Tags       []facetCount `bson:"tags"`
+		}
+		if err := cursor.All(ctx, &out); err != nil {
+			return err
+		}
+
+		if len(out) > 0 {
+			results.Artworks = toHits(out[0].Hits)
+			if len(out[0].Total) > 0 {
+				results.TotalArtworks = out[0].Total[0].Count
+			}
+			results.Facets = Facets{
+				Mediums:    toCounts(out[0].Mediums),
+				Categories: toCounts(out[0].Categories),
+				Tags:       toCounts(out[0].Tags),
+			}
+		}
+	}
+
+	if q.Kind != KindArtwork {
+		users := database.Collection("users")
+		filter := bson.M{"$text": bson.M{"$search": text}}
+
+		total, err := users.CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+		cursor, err := users.Find(ctx, filter, options.Find().
+			SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetSkip(int64(q.Offset)).
+			SetLimit(int64(q.Limit)))
+		if err != nil {
+			return err
+		}
+		var hits []scoredID
+		if err := cursor.All(ctx, &hits); err != nil {
+			return err
+		}
+		results.Artists = toHits(hits)
+		results.TotalArtists = int(total)
+	}
+	return nil
+}
+
+// tagVocabulary counts the words used in the tags of searchable artworks.
+// The counts are cached for vocabularyTTL; the map must not be modified.
+func (m *MongoIndex) tagVocabulary(ctx context.Context) (map[string]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.vocabulary != nil && time.Since(m.vocabularyAt) < vocabularyTTL {
+		return m.vocabulary, nil
+	}
+
+	cursor, err := database.Collection("artworks").Aggregate(ctx, []bson.M{
+		{"$match": m.artworkFilter},
+		{"$unwind": "$tags"},
+		{"$group": bson.M{"_id": "$tags", "n": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tags []facetCount
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	vocabulary := map[string]int{}
+	for _, t := range tags {
+		for _, word := range tokenize(t.Value) {
+			vocabulary[word] += t.Count
+		}
+	}
+	m.vocabulary, m.vocabularyAt = vocabulary, time.Now()
+	return vocabulary, nil
+}
+
+// wordPrefixFilter matches documents whose stored words, as produced by
+// Words, include each complete word of prefix and a word starting with the
+// last one. The words are lowercase, so the regex is anchored and
+// case-sensitive and can use the index on field.
+func wordPrefixFilter(field, prefix string) bson.M {
+	words := splitWords(prefix)
+	last := words[len(words)-1]
+	conditions := bson.A{bson.M{field: bson.M{"$regex": "^" + regexp.QuoteMeta(last)}}}
+	if len(words) > 1 {
+		conditions = append(conditions, bson.M{field: bson.M{"$all": words[:len(words)-1]}})
+	}
+	return bson.M{"$and": conditions}
+}
+
+func (m *MongoIndex) Suggest(ctx context.Context, q Query) ([]Suggestion, error) {
+	prefix := strings.ToLower(strings.TrimSpace(q.Text))
+	suggestions := []Suggestion{}
+	if prefix == "" {
+		return suggestions, nil
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+
+	tagPattern := "^" + regexp.QuoteMeta(strings.ReplaceAll(prefix, " ", "-"))
+	filter := m.filter(Query{HideMature: q.HideMature})
+	filter["tags"] = bson.M{"$regex": tagPattern}
+	cursor, err := database.Collection("artworks").Aggregate(ctx, []bson.M{
+		{"$match": filter},
+		{"$unwind": "$tags"},
+		{"$match": bson.M{"tags": bson.M{"$regex": tagPattern}}},
+		{"$group": bson.M{"_id": "$tags", "n": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Key: "n", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": q.Limit},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tags []facetCount
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		suggestions = append(suggestions, Suggestion{Text: t.Value, Kind: SuggestTag})
+	}
+
+	if len(splitWords(prefix)) == 0 {
+		return suggestions, nil
+	}
+
+	var artists []struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		FullName string             `bson:"full_name"`
+	}
+	cursor, err = database.Collection("users").Find(ctx, wordPrefixFilter("search_words", prefix), options.Find().
+		SetProjection(bson.M{"full_name": 1}).
+		SetSort(bson.M{"full_name": 1}).
+		SetLimit(int64(q.Limit)))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &artists); err != nil {
+		return nil, err
+	}
+	for _, a := range artists {
+		id := a.ID
+		suggestions = append(suggestions, Suggestion{Text: a.FullName, Kind: SuggestArtist, ID: &id})
+	}
+
+	filter = m.filter(Query{HideMature: q.HideMature})
+	for k, v := range wordPrefixFilter("searchWords", prefix) {
+		filter[k] = v
+	}
+	var artworks []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Title string             `bson:"title"`
+	}
+	cursor, err = database.Collection("artworks").Find(ctx, filter, options.Find().
+		SetProjection(bson.M{"title": 1}).
+		SetSort(bson.M{"title": 1}).
+		SetLimit(int64(q.Limit)))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &artworks); err != nil {
+		return nil, err
+	}
+	for _, a := range artworks {
+		id := a.ID
+		suggestions = append(suggestions, Suggestion{Text: a.Title, Kind: SuggestArtwork, ID: &id})
+	}
+
+	if len(suggestions) > q.Limit {
+		suggestions = suggestions[:q.Limit]
+	}
+	return suggestions, nil
+}
+
+func toHits(ids []scoredID) []Hit {
+	hits := make([]Hit, len(ids))
+	for i, s := range ids {
+		hits[i] = Hit{ID: s.ID, Score: s.Score}
+	}
+	return hits
+}
+
+func toCounts(counts []facetCount) map[string]int {
+	out := make(map[string]int, len(counts))
+	for _, c := range counts {
+		out[c.Value] = c.Count
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+// Package search indexes public artworks and artists for GET /search.
+package search
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Document kinds.
+const (
+	KindArtwork = "artwork"
+	KindArtist  = "artist"
+)
+
+// Suggestion kinds. Tags complete to a tag filter rather than a document.
+const (
+	SuggestTag     = "tag"
+	SuggestArtwork = KindArtwork
+	SuggestArtist  = KindArtist
+)
+
+const maxFacetTags = 20
+
+// Document is one searchable artwork or artist. For artists, Title holds the
+// full name and Handle the portfolio slug.
+type Document struct {
+	ID            primitive.ObjectID
+	Kind          string
+	Title         string
+	Handle        string
+	Description   string
+	Tags          []string
+	Medium        string
+	Category      string
+	ArtistName    string
+	ContentRating string
+	CreatedAt     time.Time
+}
+
+// Query is a search request. Kind narrows the results to artworks or
+// artists; the remaining filters only apply to artworks.
+type Query struct {
+	Text       string
+	Kind       string
+	Tags       []string
+	Medium     string
+	Category   string
+	HideMature bool
+	Limit      int
+	Offset     int
+}
+
+type Hit struct {
+	ID    primitive.ObjectID `json:"id"`
+	Score float64            `json:"score"`
+}
+
+// Facets count the matching artworks per value, before Limit and Offset.
+type Facets struct {
+	Mediums    map[string]int `json:"mediums"`
+	Categories map[string]int `json:"categories"`
+	Tags       map[string]int `json:"tags"`
+}
+
+// Results holds the ranked hits per kind. Corrected is set when typo
+// correction changed the query.
+type Results struct {
+	Artworks      []Hit
+	Artists       []Hit
+	TotalArtworks int
+	TotalArtists  int
+	Facets        Facets
+	Corrected     string
+}
+
+type Suggestion struct {
+	Text string              `json:"text"`
+	Kind string              `json:"kind"`
+	ID   *primitive.ObjectID `json:"id,omitempty"`
+}
+
+// Index ranks documents for a query and completes prefixes for the search
+// box. Suggest treats q.Text as the prefix and honours Limit and HideMature.
+type Index interface {
+	Search(ctx context.Context, q Query) (Results, error)
+	Suggest(ctx context.Context, q Query) ([]Suggestion, error)
+}
+
+// Loadable is implemented by indexes that keep their own copy of the
+// documents and have to be fed from the database.
+type Loadable interface {
+	Replace(docs []Document)
+}
+
+func newFacets() Facets {
+	return Facets{Mediums: map[string]int{}, Categories: map[string]int{}, Tags: map[string]int{}}
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "by": true, "for": true, "in": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// splitWords lowercases text and splits it at anything that isn't a letter
+// or digit.
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Words returns the distinct lowercased words of text, stop words included.
+// Artwork titles and artist names are stored with their words so that
+// MongoIndex.Suggest can match prefixes with an anchored, indexed regex.
+func Words(text string) []string {
+	words := []string{}
+	seen := map[string]bool{}
+	for _, w := range splitWords(text) {
+		if !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// tokenize lowercases text and splits it into words, dropping stop words.
+// Hyphenated tags split into their parts.
+func tokenize(text string) []string {
+	words := splitWords(text)
+	tokens := words[:0]
+	for _, w := range words {
+		if !stopWords[w] {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// maxEdits is how many typos a query term tolerates. Short words get none,
+// since one edit turns them into too many other words.
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// Levenshtein plus swapping two adjacent letters as a single edit. It gives
+// up with limit+1 once the distance is known to exceed limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// correct replaces each unknown term with its closest vocabulary word,
+// preferring the more common word on ties. It reports whether anything
+// changed.
+func correct(terms []string, vocabulary map[string]int, known func(i int, term string) bool) ([]string, bool) {
+	corrected := make([]string, len(terms))
+	changed := false
+	for i, term := range terms {
+		corrected[i] = term
+		if _, ok := vocabulary[term]; ok || known(i, term) {
+			continue
+		}
+		limit := maxEdits(term)
+		best, bestDistance, bestCount := "", limit+1, 0
+		for word, count := range vocabulary {
+			d := editDistance(term, word, limit)
+			if d < bestDistance || (d == bestDistance && d <= limit && count > bestCount) {
+				best, bestDistance, bestCount = word, d, count
+			}
+		}
+		if best != "" && bestDistance <= limit {
+			corrected[i] = best
+			changed = true
+		}
+	}
+	return corrected, changed
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"The Lighthouse at Dawn", []string{"lighthouse", "at", "dawn"}},
+		{"oil-painting, sketch!", []string{"oil", "painting", "sketch"}},
+		{"of the and", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		got := tokenize(tt.text)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"The Lighthouse at Dawn", []string{"the", "lighthouse", "at", "dawn"}},
+		{"Échos d'été", []string{"échos", "d", "été"}},
+		{"dawn, DAWN & dusk", []string{"dawn", "dusk"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"portrait", "portrait", 2, 0},
+		{"portrait", "potrait", 2, 1},    // deletion
+		{"potrait", "portrait", 2, 1},    // insertion
+		{"portrait", "portrayt", 2, 1},   // substitution
+		{"portrait", "protrait", 2, 1},   // adjacent swap
+		{"landscape", "lnadscpae", 2, 2}, // two swaps
+		{"landscape", "portrait", 2, 3},  // gives up past the limit
+		{"abstract", "abs", 2, 3},        // length difference alone exceeds it
+		{"café", "cafe", 1, 1},           // runes, not bytes
+		{"watercolour", "watercolor", 1, 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	tests := []struct {
+		term string
+		want int
+	}{
+		{"ink", 0},
+		{"oils", 1},
+		{"charcoal", 2},
+	}
+	for _, tt := range tests {
+		if got := maxEdits(tt.term); got != tt.want {
+			t.Errorf("maxEdits(%q) = %d, want %d", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	vocabulary := map[string]int{"portrait": 5, "landscape": 3, "paint": 4, "point": 1, "ink": 2}
+	none := func(int, string) bool { return false }
+
+	tests := []struct {
+		name    string
+		terms   []string
+		known   func(int, string) bool
+		want    []string
+		changed bool
+	}{
+		{"known words", []string{"portrait", "ink"}, none, []string{"portrait", "ink"}, false},
+		{"one typo", []string{"potrait"}, none, []string{"portrait"}, true},
+		{"only the typo changes", []string{"ink", "landscpe"}, none, []string{"ink", "landscape"}, true},
+		{"short words are left alone", []string{"inx"}, none, []string{"inx"}, false},
+		{"too far off", []string{"zzzzzzzz"}, none, []string{"zzzzzzzz"}, false},
+		{"ties go to the commoner word", []string{"pbint"}, none, []string{"paint"}, true},
+		{"known terms are kept", []string{"potrait"}, func(int, string) bool { return true }, []string{"potrait"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := correct(tt.terms, vocabulary, tt.known)
+			if !reflect.DeepEqual(got, tt.want) || changed != tt.changed {
+				t.Errorf("correct(%q) = %q, %v; want %q, %v", tt.terms, got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
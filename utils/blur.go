@@ -0,0 +1,6 @@
+package utils
+
+// BlurTransformation is strong enough that a gated image is unrecognisable
+// but still hints at its colours. It is applied when a blurred preview is
+// uploaded, so the stored preview never holds the sharp image.
+const BlurTransformation = "e_blur:2000,q_auto:low"
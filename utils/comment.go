@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// MaxCommentLength caps a comment, in characters.
+const MaxCommentLength = 2000
+
+var (
+	ErrCommentEmpty   = errors.New("comment cannot be empty")
+	ErrCommentTooLong = errors.New("comment is too long")
+)
+
+var commentPolicy = bluemonday.StrictPolicy()
+
+// SanitizeComment reduces a comment to plain text: markup is stripped,
+// entities are decoded and runs of blank lines are collapsed. Stripping
+// repeats until nothing changes so encoded tags cannot survive decoding.
+// Clients must still escape the result when rendering it.
+func SanitizeComment(source string) (string, error) {
+	text := source
+	for i := 0; i < 5; i++ {
+		stripped := html.UnescapeString(commentPolicy.Sanitize(text))
+		if stripped == text {
+			break
+		}
+		text = stripped
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	kept := lines[:0]
+	blank := 0
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			blank++
+			if blank > 1 {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		kept = append(kept, line)
+	}
+	text = strings.TrimSpace(strings.Join(kept, "\n"))
+
+	if text == "" {
+		return "", ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(text) > MaxCommentLength {
+		return "", ErrCommentTooLong
+	}
+	return text, nil
+}
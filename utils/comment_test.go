@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeComment(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+		err    error
+	}{
+		{"plain text", "Lovely colours!", "Lovely colours!", nil},
+		{"strips tags", "<b>bold</b> and <i>italic</i>", "bold and italic", nil},
+		{"drops script", "nice<script>alert(1)</script>", "nice", nil},
+		{"drops event handlers", `<img src=x onerror="alert(1)">great`, "great", nil},
+		{"decodes entities", "cats &amp; dogs &lt;3", "cats & dogs <3", nil},
+		{"encoded tags", "&lt;script&gt;alert(1)&lt;/script&gt;hi", "hi", nil},
+		{"double encoded tags", "&amp;lt;b&amp;gt;hi&amp;lt;/b&amp;gt;", "hi", nil},
+		{"nested tags", "<div><p>one <span>two</span></p></div>", "one two", nil},
+		{"unclosed tag", "<b>bold text", "bold text", nil},
+		{"stray angle bracket", "2 < 3 and 5 > 4", "2 < 3 and 5 > 4", nil},
+		{"collapses blank lines", "one\n\n\n\ntwo", "one\n\ntwo", nil},
+		{"normalizes line endings", "one\r\ntwo  \r\n", "one\ntwo", nil},
+		{"empty", "", "", ErrCommentEmpty},
+		{"whitespace only", "  \n\t \r\n ", "", ErrCommentEmpty},
+		{"markup only", "<p> </p><br>", "", ErrCommentEmpty},
+		{"at limit", strings.Repeat("a", MaxCommentLength), strings.Repeat("a", MaxCommentLength), nil},
+		{"over limit", strings.Repeat("a", MaxCommentLength+1), "", ErrCommentTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SanitizeComment(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
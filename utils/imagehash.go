@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+	"strconv"
+)
+
+// HashBands is the number of 8-bit bands a 64-bit hash is split into for
+// indexing. Two hashes within HashBands-1 bits of each other are guaranteed
+// to share at least one band, so an exact match on any band is a cheap
+// pre-filter for a Hamming distance search.
+const HashBands = 8
+
+// MaxDecodePixels caps the canvas ImagePrintBytes will decode. A few bytes
+// of PNG or GIF can declare a canvas whose pixels would not fit in memory.
+const MaxDecodePixels = 40_000_000
+
+var ErrImageTooLarge = errors.New("image dimensions too large")
+
+// DHash computes a 64-bit difference hash of an image. The image is reduced
+// to a 9x8 grayscale grid and each bit records whether a cell is brighter
+// than its right-hand neighbour, which survives rescaling, recompression and
+// small colour edits.
+func DHash(img image.Image) uint64 {
+	const w, h = 9, 8
+
+	bounds := img.Bounds()
+	var grid [h][w]float64
+
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/h
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/w
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var sum float64
+			var n int
+			for py := y0; py < y1 && py < bounds.Max.Y; py++ {
+				for px := x0; px < x1 && px < bounds.Max.X; px++ {
+					r, g, b, _ := img.At(px, py).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					n++
+				}
+			}
+			if n > 0 {
+				grid[y][x] = sum / float64(n)
+			}
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w-1; x++ {
+			hash <<= 1
+			if grid[y][x] > grid[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// ImagePrint is what is derived from an image's pixels at upload: the
+// perceptual hash for duplicate checks and the palette for recommendations.
+type ImagePrint struct {
+	Hash    uint64
+	Palette []string
+}
+
+// ImagePrintBytes decodes an encoded image once and derives its print. The
+// header is checked first so oversized canvases are refused before any
+// pixels are allocated.
+func ImagePrintBytes(data []byte) (ImagePrint, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ImagePrint{}, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxDecodePixels {
+		return ImagePrint{}, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ImagePrint{}, err
+	}
+	return ImagePrint{Hash: DHash(img), Palette: Palette(img)}, nil
+}
+
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func FormatHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+func ParseHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// HashToBands splits a hash into HashBands index keys of the form
+// "<band>:<byte>" so that equal bytes in different positions don't collide.
+func HashToBands(hash uint64) []string {
+	bands := make([]string, HashBands)
+	for i := 0; i < HashBands; i++ {
+		b := byte(hash >> (8 * (HashBands - 1 - i)))
+		bands[i] = fmt.Sprintf("%d:%02x", i, b)
+	}
+	return bands
+}
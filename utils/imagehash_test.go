@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 200, G: 40, B: 40, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{R: 40, G: 40, B: 200, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withDimensions rewrites a PNG's IHDR to declare another canvas size,
+// leaving the pixel data as it was.
+func withDimensions(data []byte, w, h uint32) []byte {
+	out := append([]byte{}, data...)
+	// Signature (8), chunk length (4), "IHDR" (4), then width and height.
+	binary.BigEndian.PutUint32(out[16:], w)
+	binary.BigEndian.PutUint32(out[20:], h)
+	crc := crc32.ChecksumIEEE(out[12:29])
+	binary.BigEndian.PutUint32(out[29:], crc)
+	return out
+}
+
+func TestImagePrintBytes(t *testing.T) {
+	small := encodePNG(t, 16, 16)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"ordinary image", small, nil},
+		{"declared canvas too large", withDimensions(small, 100_000, 100_000), ErrImageTooLarge},
+		{"just over the cap", withDimensions(small, MaxDecodePixels/1000+1, 1000), ErrImageTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ImagePrintBytes(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(got.Palette) == 0 {
+				t.Error("expected a palette for a decodable image")
+			}
+		})
+	}
+
+	if _, err := ImagePrintBytes([]byte("not an image")); err == nil {
+		t.Error("expected an error for bytes that are not an image")
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/nerokome/artfolio-backend/models"
+)
+
+// ArtworkJSONLD describes an artwork as a schema.org VisualArtwork for
+// embedding in a <script type="application/ld+json"> tag.
+func ArtworkJSONLD(artwork *models.Artwork, artistName string, description string) map[string]interface{} {
+	doc := map[string]interface{}{
+		"@context":    "https://schema.org",
+		"@type":       "VisualArtwork",
+		"name":        artwork.Title,
+		"dateCreated": artwork.CreatedAt.Format(time.RFC3339),
+		"creator": map[string]interface{}{
+			"@type": "Person",
+			"name":  artistName,
+		},
+	}
+	if artwork.URL != "" {
+		doc["image"] = artwork.URL
+	}
+	if description != "" {
+		doc["description"] = description
+	}
+	if len(artwork.Tags) > 0 {
+		doc["keywords"] = artwork.Tags
+	}
+	if label, ok := models.Mediums[artwork.Medium]; ok {
+		doc["artMedium"] = label
+	}
+	if label, ok := models.Categories[artwork.Category]; ok {
+		doc["artform"] = label
+	}
+
+	if l := artwork.License; l != nil {
+		info := models.LicenseTypes[l.Type]
+		switch {
+		case info.URL != "":
+			doc["license"] = info.URL
+		case l.Type == models.LicenseCustom:
+			doc["license"] = l.CustomText
+		default:
+			doc["license"] = info.Name
+		}
+		if l.CopyrightHolder != "" {
+			doc["copyrightHolder"] = map[string]interface{}{"@type": "Person", "name": l.CopyrightHolder}
+		}
+		if l.CopyrightYear != 0 {
+			doc["copyrightYear"] = l.CopyrightYear
+		}
+		if l.Attribution != "" {
+			doc["creditText"] = l.Attribution
+		}
+		doc["copyrightNotice"] = copyrightNotice(l)
+	}
+	return doc
+}
+
+func copyrightNotice(l *models.License) string {
+	notice := "©"
+	if l.CopyrightYear != 0 {
+		notice += " " + strconv.Itoa(l.CopyrightYear)
+	}
+	if l.CopyrightHolder != "" {
+		notice += " " + l.CopyrightHolder
+	}
+	return notice
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// MaxDescriptionLength caps a Markdown description, in characters.
+const MaxDescriptionLength = 5000
+
+var ErrDescriptionTooLong = errors.New("description is too long")
+
+// ugcLinks marks every link as user-generated; the sanitizer adds nofollow.
+type ugcLinks struct{}
+
+func (ugcLinks) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindLink, ast.KindAutoLink:
+			n.SetAttributeString("rel", []byte("ugc"))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// Raw HTML in the source is dropped by goldmark (unsafe rendering is off);
+// the sanitizer is a second line of defence over the rendered output.
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(ugcLinks{}, 100)),
+		),
+	)
+
+	descriptionPolicy = func() *bluemonday.Policy {
+		p := bluemonday.UGCPolicy()
+		p.AllowAttrs("rel").Matching(regexp.MustCompile(`^ugc$`)).OnElements("a")
+		p.RequireNoFollowOnLinks(true)
+		p.AddTargetBlankToFullyQualifiedLinks(true)
+		return p
+	}()
+
+	textPolicy = bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true)
+)
+
+// RenderMarkdown converts a user-written Markdown description into sanitized
+// HTML. Links get rel="ugc nofollow"; scripts, raw HTML and unsafe URLs are
+// removed.
+func RenderMarkdown(source string) (string, error) {
+	if utf8.RuneCountInString(source) > MaxDescriptionLength {
+		return "", ErrDescriptionTooLong
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return descriptionPolicy.Sanitize(buf.String()), nil
+}
+
+// PlainTextExcerpt strips rendered HTML down to text and shortens it to at
+// most max characters, cutting at a word boundary.
+func PlainTextExcerpt(renderedHTML string, max int) string {
+	plain := html.UnescapeString(textPolicy.Sanitize(renderedHTML))
+	plain = strings.Join(strings.Fields(plain), " ")
+
+	runes := []rune(plain)
+	if len(runes) <= max {
+		return plain
+	}
+
+	cut := string(runes[:max-1])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "emphasis",
+			source:   "some **bold** text",
+			contains: []string{"<strong>bold</strong>"},
+		},
+		{
+			name:     "script tag",
+			source:   "hello <script>alert(1)</script> world",
+			contains: []string{"hello"},
+			excludes: []string{"<script", "alert(1)</script>"},
+		},
+		{
+			name:     "script block",
+			source:   "<script>\nalert(1)\n</script>",
+			excludes: []string{"<script"},
+		},
+		{
+			name:     "javascript link",
+			source:   "[click](javascript:alert(1))",
+			excludes: []string{"javascript:", "href"},
+		},
+		{
+			name:     "encoded javascript link",
+			source:   "[click](jav&#x61;script:alert(1))",
+			excludes: []string{"javascript:", "href"},
+		},
+		{
+			name:     "raw html",
+			source:   `<div onclick="steal()">hi</div> <img src="x" onerror="steal()">`,
+			excludes: []string{"<div", "onclick", "onerror", "<img"},
+		},
+		{
+			name:     "inline raw html",
+			source:   `text <iframe src="https://example.com"></iframe>`,
+			contains: []string{"text"},
+			excludes: []string{"<iframe"},
+		},
+		{
+			name:     "link",
+			source:   "[site](https://example.com)",
+			contains: []string{`href="https://example.com"`, `rel="ugc nofollow noopener"`, `target="_blank"`},
+		},
+		{
+			name:     "autolink",
+			source:   "see https://example.com",
+			contains: []string{`href="https://example.com"`, `rel="ugc nofollow noopener"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMarkdown(tt.source)
+			if err != nil {
+				t.Fatalf("RenderMarkdown: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("output %q does not contain %q", got, s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("output %q contains %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    error
+	}{
+		{"at limit", strings.Repeat("a", MaxDescriptionLength), nil},
+		{"over limit", strings.Repeat("a", MaxDescriptionLength+1), ErrDescriptionTooLong},
+		// The limit counts characters, not bytes.
+		{"multibyte at limit", strings.Repeat("é", MaxDescriptionLength), nil},
+		{"multibyte over limit", strings.Repeat("é", MaxDescriptionLength+1), ErrDescriptionTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderMarkdown(tt.source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPlainTextExcerpt(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		max  int
+		want string
+	}{
+		{"strips tags", "<p>hello <strong>there</strong></p>", 100, "hello there"},
+		{"decodes entities", "<p>cats &amp; dogs</p>", 100, "cats & dogs"},
+		{"collapses whitespace", "<p>one</p>\n\n<p>two</p>", 100, "one two"},
+		{"drops script text", "<p>ok</p><script>alert(1)</script>", 100, "ok"},
+		{"cuts at word", "<p>the quick brown fox jumps</p>", 17, "the quick brown…"},
+		{"trims punctuation", "<p>first, second, third</p>", 14, "first, second…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlainTextExcerpt(tt.html, tt.max)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if utf8.RuneCountInString(got) > tt.max {
+				t.Errorf("excerpt %q is longer than %d characters", got, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// SniffContentType extends http.DetectContentType with the ISO-BMFF video
+// containers it doesn't recognise (QuickTime and most non-"mp4" brands).
+func SniffContentType(data []byte) string {
+	contentType := http.DetectContentType(data)
+	if contentType != "application/octet-stream" || len(data) < 12 {
+		return contentType
+	}
+
+	if bytes.Equal(data[4:8], []byte("ftyp")) {
+		if bytes.Equal(data[8:12], []byte("qt  ")) {
+			return "video/quicktime"
+		}
+		return "video/mp4"
+	}
+	return contentType
+}
+
+func IsVideoContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "video/")
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// PaletteSize is the most colours Palette returns.
+const PaletteSize = 5
+
+// paletteGrid is how many sample points are taken along each axis; a 48x48
+// sample is plenty to find the dominant colours of any artwork.
+const paletteGrid = 48
+
+// minPaletteShare drops colours covering less of the image than this.
+const minPaletteShare = 0.03
+
+// Palette returns up to PaletteSize dominant colours of img as "#rrggbb",
+// most prominent first. Pixels are bucketed at 3 bits per channel and each
+// bucket reports the average of its pixels; mostly transparent pixels are
+// ignored.
+func Palette(img image.Image) []string {
+	type bucket struct {
+		r, g, b float64
+		n       int
+	}
+	buckets := map[int]*bucket{}
+	total := 0
+
+	bounds := img.Bounds()
+	for y := 0; y < paletteGrid; y++ {
+		py := bounds.Min.Y + (2*y+1)*bounds.Dy()/(2*paletteGrid)
+		for x := 0; x < paletteGrid; x++ {
+			px := bounds.Min.X + (2*x+1)*bounds.Dx()/(2*paletteGrid)
+			r, g, b, a := img.At(px, py).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo premultiplied alpha and scale to 8 bits.
+			r8, g8, b8 := float64(r)*255/float64(a), float64(g)*255/float64(a), float64(b)*255/float64(a)
+			key := int(r8)>>5<<6 | int(g8)>>5<<3 | int(b8)>>5
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r, bk.g, bk.b = bk.r+r8, bk.g+g8, bk.b+b8
+			bk.n++
+			total++
+		}
+	}
+
+	ranked := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		ranked = append(ranked, bk)
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].n > ranked[j].n })
+
+	palette := []string{}
+	for _, bk := range ranked {
+		if len(palette) == PaletteSize || float64(bk.n) < minPaletteShare*float64(total) {
+			break
+		}
+		n := float64(bk.n)
+		palette = append(palette, fmt.Sprintf("#%02x%02x%02x", int(bk.r/n), int(bk.g/n), int(bk.b/n)))
+	}
+	return palette
+}
+
+func parseHexColor(s string) (r, g, b float64, ok bool) {
+	if len(s) != 7 || s[0] != '#' {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return float64(v >> 16), float64(v >> 8 & 0xff), float64(v & 0xff), true
+}
+
+// paletteMatchDistance is the RGB distance at which two colours stop
+// counting as alike.
+const paletteMatchDistance = 128.0
+
+// PaletteSimilarity scores two palettes from 0 to 1 by how close each
+// colour of one is to its nearest colour in the other, averaged both ways.
+func PaletteSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	nearest := func(from, to []string) float64 {
+		sum, n := 0.0, 0
+		for _, c := range from {
+			r1, g1, b1, ok := parseHexColor(c)
+			if !ok {
+				continue
+			}
+			best := paletteMatchDistance
+			for _, d := range to {
+				r2, g2, b2, ok := parseHexColor(d)
+				if !ok {
+					continue
+				}
+				best = math.Min(best, math.Sqrt((r1-r2)*(r1-r2)+(g1-g2)*(g1-g2)+(b1-b2)*(b1-b2)))
+			}
+			sum += 1 - best/paletteMatchDistance
+			n++
+		}
+		if n == 0 {
+			return 0
+		}
+		return sum / float64(n)
+	}
+	return (nearest(a, b) + nearest(b, a)) / 2
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrBlockedAddress   = errors.New("destination address is not allowed")
+	ErrTooManyRedirects = errors.New("too many redirects")
+	ErrResponseTooLarge = errors.New("response too large")
+	ErrUnsupportedURL   = errors.New("only http and https URLs are supported")
+)
+
+// SafeFetchOptions bound a SafeFetch call. Zero values fall back to
+// conservative defaults.
+type SafeFetchOptions struct {
+	MaxBytes     int64
+	Timeout      time.Duration
+	MaxRedirects int
+}
+
+// SafeFetchResult is a fully read response body.
+type SafeFetchResult struct {
+	Data        []byte
+	ContentType string
+	FinalURL    string
+}
+
+// blockedNets covers ranges that are private, loopback, link-local or
+// otherwise not routable on the public internet.
+var blockedNets = func() []*net.IPNet {
+	cidrs := []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.0.0.0/24",
+		"192.0.2.0/24",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"198.51.100.0/24",
+		"203.0.113.0/24",
+		"224.0.0.0/4",
+		"240.0.0.0/4",
+		"::/128",
+		"::1/128",
+		"64:ff9b::/96",
+		"fc00::/7",
+		"fe80::/10",
+		"ff00::/8",
+	}
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, _ := net.ParseCIDR(c)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsPublicIP reports whether ip is a globally routable unicast address.
+func IsPublicIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowIP decides which resolved addresses SafeFetch may connect to. Tests
+// replace it to reach httptest servers on loopback.
+var allowIP = IsPublicIP
+
+// SafeFetch downloads a URL supplied by a user. The address check runs on the
+// socket after DNS resolution, so it also covers redirects and DNS
+// rebinding; every redirect hop is re-validated and counted.
+func SafeFetch(ctx context.Context, rawURL string, opts SafeFetchOptions) (*SafeFetchResult, error) {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = 10 * 1024 * 1024
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 15 * time.Second
+	}
+	if opts.MaxRedirects <= 0 {
+		opts.MaxRedirects = 3
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrUnsupportedURL
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !allowIP(ip) {
+				return ErrBlockedAddress
+			}
+			return nil
+		},
+	}
+
+	client := &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: opts.Timeout,
+			MaxIdleConns:          1,
+			DisableKeepAlives:     true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > opts.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnsupportedURL
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Artfolio-Importer/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Unwrap our own errors from the url.Error / net.OpError chain so
+		// callers can match on them.
+		for _, known := range []error{ErrBlockedAddress, ErrTooManyRedirects, ErrUnsupportedURL} {
+			if errors.Is(err, known) {
+				return nil, known
+			}
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote server returned %d", resp.StatusCode)
+	}
+	if resp.ContentLength > opts.MaxBytes {
+		return nil, ErrResponseTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, opts.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > opts.MaxBytes {
+		return nil, ErrResponseTooLarge
+	}
+
+	return &SafeFetchResult{
+		Data:        data,
+		ContentType: resp.Header.Get("Content-Type"),
+		FinalURL:    resp.Request.URL.String(),
+	}, nil
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// allowLoopback lets SafeFetch reach httptest servers for the rest of the
+// test.
+func allowLoopback(t *testing.T) {
+	t.Helper()
+	allowIP = func(net.IP) bool { return true }
+	t.Cleanup(func() { allowIP = IsPublicIP })
+}
+
+func TestSafeFetchBlocksPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pngHeader)
+	}))
+	defer srv.Close()
+
+	_, err := SafeFetch(context.Background(), srv.URL, SafeFetchOptions{})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("loopback fetch: got %v, want %v", err, ErrBlockedAddress)
+	}
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "::1", "fd00::1"} {
+		if IsPublicIP(net.ParseIP(ip)) {
+			t.Errorf("IsPublicIP(%s) = true, want false", ip)
+		}
+	}
+	if !IsPublicIP(net.ParseIP("93.184.216.34")) {
+		t.Error("IsPublicIP(93.184.216.34) = false, want true")
+	}
+}
+
+func TestSafeFetchRejectsUnsupportedURLs(t *testing.T) {
+	for _, u := range []string{"file:///etc/passwd", "ftp://example.com/a.png", "http://", "not a url"} {
+		if _, err := SafeFetch(context.Background(), u, SafeFetchOptions{}); !errors.Is(err, ErrUnsupportedURL) {
+			t.Errorf("SafeFetch(%q): got %v, want %v", u, err, ErrUnsupportedURL)
+		}
+	}
+}
+
+func TestSafeFetchRedirectLimit(t *testing.T) {
+	allowLoopback(t)
+
+	// /hop/N redirects to /hop/N-1; /hop/0 serves the image.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hop/{n}", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.PathValue("n"))
+		if n <= 0 {
+			w.Write(pngHeader)
+			return
+		}
+		http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	res, err := SafeFetch(context.Background(), srv.URL+"/hop/2", SafeFetchOptions{MaxRedirects: 2})
+	if err != nil {
+		t.Fatalf("two redirects: %v", err)
+	}
+	if !strings.HasSuffix(res.FinalURL, "/hop/0") {
+		t.Errorf("FinalURL = %q, want it to end in /hop/0", res.FinalURL)
+	}
+
+	_, err = SafeFetch(context.Background(), srv.URL+"/hop/3", SafeFetchOptions{MaxRedirects: 2})
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Fatalf("three redirects: got %v, want %v", err, ErrTooManyRedirects)
+	}
+}
+
+func TestSafeFetchSizeCap(t *testing.T) {
+	allowLoopback(t)
+
+	body := append(append([]byte{}, pngHeader...), make([]byte, 1024)...)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"declared length", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(body)
+		}},
+		{"chunked", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(body[:10])
+			w.(http.Flusher).Flush()
+			w.Write(body[10:])
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			_, err := SafeFetch(context.Background(), srv.URL, SafeFetchOptions{MaxBytes: 512})
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("got %v, want %v", err, ErrResponseTooLarge)
+			}
+			res, err := SafeFetch(context.Background(), srv.URL, SafeFetchOptions{MaxBytes: int64(len(body))})
+			if err != nil {
+				t.Fatalf("at the limit: %v", err)
+			}
+			if len(res.Data) != len(body) {
+				t.Errorf("read %d bytes, want %d", len(res.Data), len(body))
+			}
+		})
+	}
+}
+
+func TestSafeFetchTimeout(t *testing.T) {
+	allowLoopback(t)
+
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+		w.Write(pngHeader)
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	start := time.Now()
+	_, err := SafeFetch(context.Background(), srv.URL, SafeFetchOptions{Timeout: 100 * time.Millisecond})
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetch took %v, want it cut off near the timeout", elapsed)
+	}
+}
+
+func TestSafeFetchNonImageBody(t *testing.T) {
+	allowLoopback(t)
+
+	// The header claims an image; the bytes are HTML.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("<!DOCTYPE html><html><body>not an image</body></html>"))
+	}))
+	defer srv.Close()
+
+	res, err := SafeFetch(context.Background(), srv.URL, SafeFetchOptions{})
+	if err != nil {
+		t.Fatalf("SafeFetch: %v", err)
+	}
+	if res.ContentType != "image/png" {
+		t.Errorf("ContentType = %q, want the header as sent", res.ContentType)
+	}
+	if got := SniffContentType(res.Data); strings.HasPrefix(got, "image/") {
+		t.Errorf("SniffContentType = %q, want a non-image type", got)
+	}
+}
+
+func TestSafeFetchNonOKStatus(t *testing.T) {
+	allowLoopback(t)
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := SafeFetch(context.Background(), srv.URL, SafeFetchOptions{}); err == nil {
+		t.Fatal("expected an error for a 404")
+	}
+}
@@ -11,11 +11,17 @@ func GenerateSlug(title string) string {
 	return slug
 }
 
-func GenerateSEO(title string, imageURL string) map[string]interface{} {
+// GenerateSEO builds page metadata for an artwork. description is a plain
+// text excerpt; when empty a generic description is used.
+func GenerateSEO(title string, imageURL string, imageAlt string, description string) map[string]interface{} {
+	if description == "" {
+		description = "Explore the artwork titled '" + title + "' on Artfolio. View engagement, style, and performance insights."
+	}
 	return map[string]interface{}{
 		"title":       title + " | Artfolio",
-		"description": "Explore the artwork titled '" + title + "' on Artfolio. View engagement, style, and performance insights.",
+		"description": description,
 		"image":       imageURL,
+		"imageAlt":    imageAlt,
 		"keywords": []string{
 			"artfolio",
 			"digital art",
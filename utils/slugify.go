@@ -18,3 +18,24 @@ func Slugify(input string) string {
 	s = reg2.ReplaceAllString(s, "-")
 	return s
 }
+
+const MaxTags = 20
+
+// NormalizeTags slugifies free-form tags, dropping empties and duplicates
+// while keeping the caller's order.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := []string{}
+	for _, t := range tags {
+		slug := strings.Trim(Slugify(strings.TrimSpace(t)), "-")
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		out = append(out, slug)
+		if len(out) == MaxTags {
+			break
+		}
+	}
+	return out
+}
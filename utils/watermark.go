@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nerokome/artfolio-backend/models"
+)
+
+// WatermarkTransformation builds the Cloudinary transformation string that
+// overlays a user's watermark. It returns "" when watermarking is disabled or
+// the settings are incomplete.
+func WatermarkTransformation(w *models.WatermarkSettings) string {
+	if w == nil || !w.Enabled {
+		return ""
+	}
+
+	gravity, ok := models.WatermarkPositions[w.Position]
+	if !ok {
+		gravity = "south_east"
+	}
+
+	var layer string
+	switch w.Type {
+	case models.WatermarkText:
+		if strings.TrimSpace(w.Text) == "" {
+			return ""
+		}
+		// Commas and slashes delimit transformation components, so they must
+		// be escaped inside the text itself.
+		text := url.PathEscape(w.Text)
+		text = strings.ReplaceAll(text, ",", "%2C")
+		text = strings.ReplaceAll(text, "/", "%2F")
+		layer = "l_text:Arial_64_bold:" + text + ",co_white"
+	case models.WatermarkLogo:
+		if w.LogoPublicID == "" {
+			return ""
+		}
+		layer = "l_" + strings.ReplaceAll(w.LogoPublicID, "/", ":")
+	default:
+		return ""
+	}
+
+	return fmt.Sprintf("%s,o_%d,w_%.2f,fl_relative/fl_layer_apply,g_%s,x_0.03,y_0.03",
+		layer, w.Opacity, w.Scale, gravity)
+}